@@ -241,7 +241,19 @@ CREATE TABLE public.results (
     fullname character varying,
     launch_id bigint NOT NULL,
     parent_suite character varying,
-    sub_suite character varying
+    sub_suite character varying,
+    befores json,
+    afters json,
+    attempts smallint,
+    status_details json,
+    parameters json,
+    links json,
+    stage character varying,
+    history_id character varying,
+    start bigint,
+    stop bigint,
+    attachments json,
+    labels json
 );
 
 
@@ -265,6 +277,14 @@ ALTER TABLE ONLY public.launches
     ADD CONSTRAINT launches_pkey PRIMARY KEY (id);
 
 
+--
+-- Name: launches launches_name_key; Type: CONSTRAINT; Schema: public; Owner: supabase_admin
+--
+
+ALTER TABLE ONLY public.launches
+    ADD CONSTRAINT launches_name_key UNIQUE (name);
+
+
 --
 -- TOC entry 2727 (class 2606 OID 17176)
 -- Name: projects projects_pkey; Type: CONSTRAINT; Schema: public; Owner: supabase_admin
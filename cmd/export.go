@@ -0,0 +1,88 @@
+/*
+Package cmd contains all the commands that are available in the test-inspector CLI.
+Copyright © 2022 Egor Romanov egor@supabase.io
+*/
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"test-inspector/internal/supabase"
+	"test-inspector/pkg/ctrf"
+	"test-inspector/pkg/models"
+	"test-inspector/pkg/report"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var (
+	output         string
+	exportLaunchID int64
+)
+
+// exportCmd represents the export command
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "export local results or results of the uploaded launch to ctrf json",
+
+	Run: func(cmd *cobra.Command, args []string) {
+		var results []models.SupaResult
+		if exportLaunchID != 0 {
+			supa, err := supabase.CreateClient(host, SupabaseKey, supabase.UserCredentials{
+				Email:    user,
+				Password: password,
+			})
+			if err != nil {
+				fmt.Printf("error trying to connect to supabase: %v", err)
+				return
+			}
+			results, err = supa.GetResults(exportLaunchID)
+			if err != nil {
+				fmt.Printf("error trying to retrieve launch results: %v", err)
+				return
+			}
+		} else {
+			parsed, err := report.ReadAll(reportType, resultsPaths)
+			if err = checkDiagnostics(err); err != nil {
+				fmt.Printf("error trying to parse results folder: %v\n", err)
+				os.Exit(1)
+			}
+			for _, r := range parsed {
+				results = append(results, r)
+			}
+		}
+
+		var w io.Writer = os.Stdout
+		if output != "-" {
+			f, err := os.Create(output)
+			if err != nil {
+				fmt.Printf("error trying to create output file: %v", err)
+				return
+			}
+			defer f.Close()
+			w = f
+		}
+		if err := ctrf.Write(w, ctrf.NewReport(ctrf.Tool, results)); err != nil {
+			fmt.Printf("error trying to write ctrf report: %v", err)
+			return
+		}
+		if output != "-" {
+			fmt.Printf("%d test results exported to %s\n", len(results), output)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(exportCmd)
+
+	exportCmd.Flags().StringVarP(
+		&output, "output", "o", "ctrf-report.json", "path to the ctrf report, use - for stdout")
+	exportCmd.Flags().Int64VarP(
+		&exportLaunchID, "launchID", "L", 0,
+		"export results of the uploaded launch instead of the local results (optional)")
+
+	viper.BindPFlag("output", exportCmd.Flags().Lookup("output"))
+	viper.BindPFlag("launchID", exportCmd.Flags().Lookup("launchID"))
+}
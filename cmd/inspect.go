@@ -5,20 +5,22 @@ Copyright © 2022 Egor Romanov egor@supabase.io
 package cmd
 
 import (
-	"encoding/json"
 	"fmt"
 	"os"
-	"regexp"
 	"strings"
-	"sync"
 	"test-inspector/internal/supabase"
-	"test-inspector/pkg/allure"
-	"test-inspector/pkg/color"
-	"test-inspector/pkg/junit"
-	"test-inspector/pkg/models"
+	"test-inspector/pkg/inspect"
+	"test-inspector/pkg/report"
 
-	"github.com/google/uuid"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var (
+	inspectFormat string
+	inspectOutput string
+	failOnExtra   bool
+	matchBy       []string
 )
 
 // inspectCmd represents the inspect command
@@ -30,6 +32,20 @@ var inspectCmd = &cobra.Command{
 			fmt.Printf("%v", err)
 			return
 		}
+		if _, err := inspect.Get(viper.GetString("inspect.format")); err != nil {
+			fmt.Printf("%v\n", err)
+			return
+		}
+		statuses, err := inspect.CompileStatuses(viper.GetStringMapString("inspect.statuses"))
+		if err != nil {
+			fmt.Printf("error trying to read status checks: %v\n", err)
+			return
+		}
+		strategies, err := inspect.CompileStrategies(viper.GetStringSlice("inspect.match"))
+		if err != nil {
+			fmt.Printf("error trying to read match strategies: %v\n", err)
+			return
+		}
 
 		supa, err := supabase.CreateClient(host, SupabaseKey, supabase.UserCredentials{
 			Email:    user,
@@ -44,18 +60,10 @@ var inspectCmd = &cobra.Command{
 			return
 		}
 
-		var results map[uuid.UUID]models.SupaResult
-		switch reportType {
-		case "allure":
-			results, err = allure.ReadResults(resultsPath)
-		case "junit":
-			results, err = junit.ReadResults(resultsPath)
-		default:
-			err = fmt.Errorf("only 'junit' and 'allure' types supported")
-		}
-		if err != nil {
-			fmt.Printf("error trying to parse results folder: %v", err)
-			return
+		results, err := report.ReadAll(reportType, resultsPaths)
+		if err = checkDiagnostics(err); err != nil {
+			fmt.Printf("error trying to parse results folder: %v\n", err)
+			os.Exit(1)
 		}
 
 		templates, err := supa.GetTemplate(int64(versionID))
@@ -64,89 +72,20 @@ var inspectCmd = &cobra.Command{
 			return
 		}
 
-		errors := 0
-		warns := 0
-		fmt.Print(color.Blue + "Test Results comparison report:\n" + color.Reset)
-
-		fmt.Printf("\n%s%d%s test results found in local run (%s)\n",
-			color.Blue, len(results), color.Reset, resultsPath)
-		fmt.Printf("%s%d%s test results found in template run\n",
-			color.Green, len(templates), color.Reset)
-		if len(results) < len(templates) {
-			fmt.Printf("\n%sWARNING%s: number of test results in local run (%d) is less "+
-				"then number of test results in template run (%d)\n",
-				color.Yellow, color.Reset, len(results), len(templates))
-		} else if len(results) > len(templates) {
-			fmt.Printf("\n%sWARNING%s: number of test results in local run (%d) differs "+
-				"from number of test results in template run (%d)\n",
-				color.Yellow, color.Reset, len(results), len(templates))
+		opts := inspect.Options{Statuses: statuses, Extras: inspect.Warning, Strategies: strategies}
+		if viper.GetBool("inspect.failOnExtra") {
+			opts.Extras = inspect.Error
 		}
-		fmt.Print("\n")
-
-		var wg sync.WaitGroup
-		var mu sync.Mutex
-		for _, t := range templates {
-			t := t
-			wg.Add(1)
-			go func() {
-				defer wg.Done()
-				report := ""
-				r := findSameResult(t, results)
-				if r == nil {
-					report += fmt.Sprintf(
-						"%s[ERROR]%s: no test result found for template: %s - %s\n",
-						color.Red, color.Reset, t.Name, t.ParentSuite)
-					mu.Lock()
-					errors++
-					fmt.Print(report)
-					mu.Unlock()
-					return
-				}
-				if t.Status == "passed" && r.Status == "passed" &&
-					t.Steps != "" && r.Steps != "" && t.Steps != r.Steps {
-					var templateSteps []*models.StepContainer
-					err = json.Unmarshal([]byte(t.Steps), &templateSteps)
-					if err != nil {
-						mu.Lock()
-						fmt.Printf("error when unmarshal template steps: %+v", err)
-						fmt.Print(report)
-						mu.Unlock()
-						return
-					}
-					var resultSteps []*models.StepContainer
-					err = json.Unmarshal([]byte(r.Steps), &resultSteps)
-					if err != nil {
-						mu.Lock()
-						fmt.Printf("error when unmarshal result steps: %+v", err)
-						fmt.Print(report)
-						mu.Unlock()
-						return
-					}
-					stepsComp := compareSteps(templateSteps, resultSteps, t.Name, t.Name)
-					if stepsComp != "" {
-						report += fmt.Sprint(stepsComp)
-						mu.Lock()
-						fmt.Print(report)
-						warns++
-						mu.Unlock()
-						return
-					}
-					mu.Lock()
-					fmt.Print(report)
-					mu.Unlock()
-					return
-				}
-			}()
+		r := inspect.Compare(templates, results, opts)
+		r.LocalPaths = resultsPaths
+		if err = writeInspectReport(r); err != nil {
+			fmt.Printf("error trying to write inspect report: %v\n", err)
+			os.Exit(1)
 		}
-		wg.Wait()
-
-		fmt.Printf("\n%s%d errors%s and %s%d warnings%s found\n",
-			color.Red, errors, color.Reset,
-			color.Yellow, warns, color.Reset)
-		if errors == 0 && warns == 0 {
-			fmt.Print(color.Green + "All checks passed!\n" + color.Reset)
+		// the number of errors is not the exit code, exit codes wrap at 256
+		if r.Errors > 0 {
+			os.Exit(1)
 		}
-		os.Exit(errors)
 	},
 }
 
@@ -162,71 +101,46 @@ func init() {
 	// Cobra supports local flags which will only run when this command
 	// is called directly, e.g.:
 	// inspectCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
+	inspectCmd.Flags().StringVar(
+		&inspectFormat, "format", inspect.Console,
+		"format of the report (possible values: "+strings.Join(inspect.Formats(), ", ")+")")
+	inspectCmd.Flags().StringVar(
+		&inspectOutput, "output", "-",
+		"path to the report, use - for stdout, the console report is printed too if it is a file")
+	inspectCmd.Flags().BoolVar(
+		&failOnExtra, "fail-on-extra", false,
+		"report local tests not found in the reference run as errors instead of warnings")
+	inspectCmd.Flags().StringSliceVar(
+		&matchBy, "match", inspect.DefaultStrategies,
+		"strategies to find local tests of reference tests in order (possible values: "+
+			strings.Join(inspect.Strategies(), ", ")+")")
+
+	viper.BindPFlag("inspect.format", inspectCmd.Flags().Lookup("format"))
+	viper.BindPFlag("inspect.output", inspectCmd.Flags().Lookup("output"))
+	viper.BindPFlag("inspect.failOnExtra", inspectCmd.Flags().Lookup("fail-on-extra"))
+	viper.BindPFlag("inspect.match", inspectCmd.Flags().Lookup("match"))
 }
 
-func findSameResult(
-	template models.SupaResult,
-	results map[uuid.UUID]models.SupaResult) *models.SupaResult {
-	for _, r := range results {
-		if normalizeName(r.Name) == normalizeName(template.Name) && checkSuiteNames(template, r) {
-			return &r
+// writeInspectReport prints the report to the console and writes it in the configured format.
+// The console report is not printed if the report in another format is written to stdout.
+func writeInspectReport(r *inspect.Report) error {
+	format := viper.GetString("inspect.format")
+	out := viper.GetString("inspect.output")
+	if format == inspect.Console || out != "-" {
+		if err := inspect.RenderConsole(os.Stdout, r); err != nil {
+			return err
 		}
 	}
-	return nil
-}
-
-// nolint:gocyclo // this is just trying to find a match for suite/subsuite/parentsuite
-func checkSuiteNames(template, result models.SupaResult) bool {
-	if (normalizeName(result.Suite) == normalizeName(template.Suite)) && (template.Suite != "") ||
-		(normalizeName(result.ParentSuite) == normalizeName(template.Suite)) && (template.Suite != "") ||
-		(normalizeName(result.SubSuite) == normalizeName(template.Suite)) && (template.Suite != "") ||
-		(normalizeName(result.Suite) == normalizeName(template.ParentSuite)) && (template.ParentSuite != "") ||
-		(normalizeName(result.ParentSuite) == normalizeName(template.ParentSuite)) && (template.ParentSuite != "") ||
-		(normalizeName(result.SubSuite) == normalizeName(template.ParentSuite)) && (template.ParentSuite != "") ||
-		(normalizeName(result.Suite) == normalizeName(template.SubSuite)) && (template.SubSuite != "") ||
-		(normalizeName(result.ParentSuite) == normalizeName(template.SubSuite)) && (template.SubSuite != "") ||
-		(normalizeName(result.SubSuite) == normalizeName(template.SubSuite)) && (template.SubSuite != "") {
-		return true
+	if format == inspect.Console && out == "-" {
+		return nil
 	}
-	return false
-}
-
-func compareSteps(templateSteps, resultSteps []*models.StepContainer, parent, test string) string {
-	if len(templateSteps) != len(resultSteps) {
-		return fmt.Sprintf("%s[WARN]%s: number of steps in template - %s - (%d) is not equal to "+
-			"number of steps in result (%d) for parent: %s\n",
-			color.Yellow, color.Reset, test, len(templateSteps), len(resultSteps), parent)
+	if out == "-" {
+		return inspect.Render(os.Stdout, format, r)
 	}
-	for i, t := range templateSteps {
-		if replaceAllSubstringsInBrackets(t.Name) != replaceAllSubstringsInBrackets(resultSteps[i].Name) {
-			return fmt.Sprintf("%s[WARN]%s: step name in template - %s - (%s) is not equal to "+
-				"step name in result (%s) for parent: %s, pos: %d\n",
-				color.Yellow, color.Reset, test, t.Name, resultSteps[i].Name, parent, t.Position)
-		}
-		if t.StepContainer != nil && len(t.StepContainer) != 0 {
-			if resultSteps[i].StepContainer != nil && len(resultSteps[i].StepContainer) != 0 {
-				if compRes := compareSteps(t.StepContainer, resultSteps[i].StepContainer, t.Name, test); compRes != "" {
-					return compRes
-				}
-			} else {
-				return fmt.Sprintf("%s[WARN]%s: inner steps exist in template - %s - (%s) but not "+
-					"in result (%s) for parent: %s, pos: %d\n",
-					color.Yellow, color.Reset, test, t.Name, resultSteps[i].Name, parent, t.Position)
-			}
-		}
+	f, err := os.Create(out)
+	if err != nil {
+		return err
 	}
-	return ""
-}
-
-func replaceAllSubstringsInBrackets(str string) string {
-	re := regexp.MustCompile(`\{.*\}`)
-	return re.ReplaceAllString(str, "")
-}
-
-func normalizeName(str string) string {
-	re := regexp.MustCompile(`\s|_`)
-	s := re.ReplaceAllString(str, "")
-	s = strings.ToLower(s)
-	s = strings.ReplaceAll(s, "test", "")
-	return s
+	defer f.Close()
+	return inspect.Render(f, format, r)
 }
@@ -62,6 +62,10 @@ var printCmd = &cobra.Command{
 					t.SubSuite == feature {
 					ctr++
 					fmt.Printf("\t%d. %s%s%s\n", ctr, color.Green, t.Name, color.Reset)
+					if t.Status != "passed" && t.StatusDetails != nil && t.StatusDetails.Message != nil {
+						fmt.Printf("\t\t%s%s%s: %s\n", color.Red, t.Status, color.Reset,
+							firstLine(*t.StatusDetails.Message))
+					}
 
 					if t.Steps != "" {
 						var templateSteps []*models.StepContainer
@@ -102,3 +106,11 @@ func printSteps(parent *models.StepContainer, depth int) {
 		printSteps(step, depth+1)
 	}
 }
+
+// firstLine returns the first line of the failure message, the rest is usually the trace.
+func firstLine(s string) string {
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		return s[:i]
+	}
+	return s
+}
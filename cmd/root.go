@@ -6,20 +6,38 @@ package cmd
 
 import (
 	"fmt"
+	"io"
 	"os"
+	"strings"
+	"test-inspector/pkg/allure"
+	"test-inspector/pkg/diag"
+	"test-inspector/pkg/files"
+	"test-inspector/pkg/junit"
+	"test-inspector/pkg/labelmap"
+	"test-inspector/pkg/labelrules"
+	"test-inspector/pkg/markers"
+	"test-inspector/pkg/redact"
+	"test-inspector/pkg/report"
+	"test-inspector/pkg/workers"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
 
 var (
-	cfgFile     string
-	host        string
-	user        string
-	password    string
-	resultsPath string
-	reportType  string
-	versionID   int32
+	cfgFile      string
+	host         string
+	user         string
+	password     string
+	resultsPaths []string
+	reportType   string
+	stepsPreset  string
+	labelsPreset string
+	traceLength  int
+	workersNum   int
+	showProgress bool
+	strict       bool
+	versionID    int32
 )
 
 var (
@@ -52,6 +70,7 @@ Build time: ` + BuildTime + `
 // This is called by main.main(). It only needs to happen once to the rootCmd.
 func Execute() {
 	err := rootCmd.Execute()
+	files.CloseArchives()
 	if err != nil {
 		os.Exit(1)
 	}
@@ -76,13 +95,37 @@ func init() {
 	rootCmd.PersistentFlags().StringVarP(
 		&password, "password", "w", "",
 		"test-inspector user password")
-	rootCmd.PersistentFlags().StringVarP(
-		&resultsPath, "resultsPath", "f", "./allure-results",
-		"path to the directory with allure results")
+	rootCmd.PersistentFlags().StringArrayVarP(
+		&resultsPaths, "resultsPath", "f", []string{"./allure-results"},
+		"path or glob pattern to the results file, folder or .zip, .tar, .tar.gz, .tgz archive, "+
+			"can be repeated to merge results of multiple shards")
 	rootCmd.PersistentFlags().Int32VarP(
 		&versionID, "versionID", "v", 0, "version ID in test-inspector (required)")
 	rootCmd.PersistentFlags().StringVarP(
-		&reportType, "type", "t", "allure", "report type (possible values: allure, junit)")
+		&reportType, "type", "t", report.Auto,
+		"report type (possible values: "+strings.Join(report.Types(), ", ")+")")
+
+	rootCmd.PersistentFlags().IntVar(
+		&workersNum, "workers", workers.Default(),
+		"number of files read and results uploaded at the same time")
+	rootCmd.PersistentFlags().BoolVar(
+		&showProgress, "progress", false,
+		"print the progress of reading and uploading results to stderr")
+	rootCmd.PersistentFlags().BoolVar(
+		&strict, "strict", false,
+		"fail if any results file is malformed instead of skipping it")
+
+	rootCmd.PersistentFlags().StringVar(
+		&stepsPreset, "steps", "",
+		"step markers preset to build junit steps from the test output (possible values: "+
+			strings.Join(markers.Presets(), ", ")+")")
+	rootCmd.PersistentFlags().StringVar(
+		&labelsPreset, "labels", "",
+		"label rules preset to derive junit suites (possible values: "+
+			strings.Join(labelrules.Presets(), ", ")+")")
+	rootCmd.PersistentFlags().IntVar(
+		&traceLength, "traceLength", redact.DefaultTraceLength,
+		"number of characters of failure traces to keep, 0 keeps the whole trace")
 
 	// Cobra also supports local flags, which will only run
 	// when this action is called directly.
@@ -92,6 +135,12 @@ func init() {
 	viper.BindPFlag("resultsPath", rootCmd.PersistentFlags().Lookup("resultsPath"))
 	viper.BindPFlag("versionID", rootCmd.PersistentFlags().Lookup("versionID"))
 	viper.BindPFlag("type", rootCmd.PersistentFlags().Lookup("type"))
+	viper.BindPFlag("workers", rootCmd.PersistentFlags().Lookup("workers"))
+	viper.BindPFlag("progress", rootCmd.PersistentFlags().Lookup("progress"))
+	viper.BindPFlag("strict", rootCmd.PersistentFlags().Lookup("strict"))
+	viper.BindPFlag("steps.preset", rootCmd.PersistentFlags().Lookup("steps"))
+	viper.BindPFlag("labels.preset", rootCmd.PersistentFlags().Lookup("labels"))
+	viper.BindPFlag("details.traceLength", rootCmd.PersistentFlags().Lookup("traceLength"))
 }
 
 // initConfig reads in config file and ENV variables if set.
@@ -116,6 +165,82 @@ func initConfig() {
 	if err := viper.ReadInConfig(); err == nil {
 		fmt.Fprintln(os.Stderr, "Using config file:", viper.ConfigFileUsed())
 	}
+
+	cobra.CheckErr(configureParsers())
+}
+
+// configureParsers registers parsers configured in the config file instead of the default ones
+// and sets the mapping of labels and the redactor of failure details applied to results of all parsers.
+func configureParsers() error {
+	steps, err := markers.Compile(markers.Config{
+		Preset:  viper.GetString("steps.preset"),
+		Source:  viper.GetString("steps.source"),
+		Markers: viper.GetStringSlice("steps.markers"),
+	})
+	if err != nil {
+		return err
+	}
+
+	labelsConfig := labelrules.Config{Preset: viper.GetString("labels.preset")}
+	if err = viper.UnmarshalKey("labels.rules", &labelsConfig.Rules); err != nil {
+		return fmt.Errorf("error trying to read label rules: %v", err)
+	}
+	labels, err := labelrules.Compile(labelsConfig)
+	if err != nil {
+		return err
+	}
+
+	report.Register(junit.Parser{Steps: steps, Labels: labels})
+	report.Register(allure.Parser{Workers: workerCount(), Progress: progressWriter()})
+
+	mapping, err := labelmap.Compile(viper.GetStringMapString("mapping"))
+	if err != nil {
+		return err
+	}
+	report.SetMapping(mapping)
+
+	redactor, err := redact.Compile(redact.Config{
+		TraceLength: viper.GetInt("details.traceLength"),
+		Patterns:    viper.GetStringSlice("details.redact"),
+	})
+	if err != nil {
+		return err
+	}
+	report.SetRedactor(redactor)
+	return nil
+}
+
+// workerCount returns the configured number of workers, the number of CPUs by default.
+func workerCount() int {
+	if n := viper.GetInt("workers"); n > 0 {
+		return n
+	}
+	return workers.Default()
+}
+
+// progressWriter returns where to print the progress, nil if it is disabled.
+func progressWriter() io.Writer {
+	if viper.GetBool("progress") {
+		return os.Stderr
+	}
+	return nil
+}
+
+// checkDiagnostics prints diagnostics of skipped files and their summary to stderr
+// and returns the fatal error of the parser. In strict mode skipped files are an error too.
+func checkDiagnostics(err error) error {
+	diagnostics, err := diag.From(err)
+	if err != nil || len(diagnostics) == 0 {
+		return err
+	}
+	for _, d := range diagnostics {
+		fmt.Fprintln(os.Stderr, d)
+	}
+	fmt.Fprintln(os.Stderr, diagnostics.Summary())
+	if viper.GetBool("strict") && diagnostics.Errors() > 0 {
+		return fmt.Errorf("strict mode: %d malformed files", diagnostics.Errors())
+	}
+	return nil
 }
 
 func validateFlags() error {
@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"errors"
+	"strings"
+	"test-inspector/pkg/diag"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+func TestCheckDiagnostics(t *testing.T) {
+	skipped := diag.Diagnostics{{File: "a-result.json", Kind: diag.Syntax, Reason: "invalid character", Severity: diag.Error}}
+	warned := diag.Diagnostics{diag.Warn("b-result.json", diag.Unsupported, "steps could not be read")}
+	fatal := errors.New("folder not found")
+	tests := []struct {
+		name   string
+		err    error
+		strict bool
+		want   string
+	}{
+		{"no diagnostics", nil, true, ""},
+		{"skipped files", skipped, false, ""},
+		{"skipped files in strict mode", skipped, true, "strict mode: 1 malformed files"},
+		// warnings do not lose tests, so they are not an error in strict mode
+		{"warnings in strict mode", warned, true, ""},
+		{"fatal error", fatal, false, "folder not found"},
+	}
+	defer viper.Set("strict", false)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			viper.Set("strict", tt.strict)
+			err := checkDiagnostics(tt.err)
+			if (err == nil) != (tt.want == "") || err != nil && !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("checkDiagnostics() = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -5,21 +5,27 @@ Copyright © 2022 Egor Romanov egor@supabase.io
 package cmd
 
 import (
+	"errors"
 	"fmt"
+	"os"
 	"sync"
 	"test-inspector/internal/supabase"
-	"test-inspector/pkg/allure"
-	"test-inspector/pkg/junit"
+	"test-inspector/pkg/attachments"
 	"test-inspector/pkg/models"
+	"test-inspector/pkg/progress"
+	"test-inspector/pkg/report"
 
-	"github.com/google/uuid"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
 
 var (
-	launch     string
-	isTemplate bool
+	launch             string
+	isTemplate         bool
+	appendRun          bool
+	withAttachments    bool
+	attachmentsBucket  string
+	attachmentsMaxSize int64
 )
 
 // uploadCmd represents the upload command
@@ -38,53 +44,84 @@ var uploadCmd = &cobra.Command{
 			Password: password,
 		})
 		if err != nil {
-			fmt.Printf("error trying to connect to supabase: %v", err)
-			return
+			fmt.Printf("error trying to connect to supabase: %v\n", err)
+			os.Exit(1)
 		}
 		if _, err = supa.GetVersion(versionID); err != nil {
-			fmt.Printf("error trying to get version: %v", err)
-			return
+			fmt.Printf("error trying to get version: %v\n", err)
+			os.Exit(1)
 		}
 
-		launchID, err := supa.CreateLaunch(models.Launch{
-			IsTemplate: isTemplate,
-			Name:       launch,
-			VersionID:  int64(versionID),
-		})
-		if err != nil || launchID == 0 {
-			fmt.Printf("error trying to create launch: %v", err)
-			return
+		// wrong paths and unknown formats are found before the launch is created
+		if err = report.Check(reportType, resultsPaths); err != nil {
+			fmt.Printf("error trying to parse results folder: %v\n", err)
+			os.Exit(1)
+		}
+		read := func(fn func(models.SupaResult) error) error {
+			return report.Stream(reportType, resultsPaths, fn)
+		}
+		if viper.GetBool("strict") {
+			// malformed files are found before the launch is created, so nothing is uploaded
+			parsed, err := report.ReadAll(reportType, resultsPaths)
+			if err = checkDiagnostics(err); err != nil {
+				fmt.Printf("error trying to parse results folder: %v\n", err)
+				os.Exit(1)
+			}
+			read = func(fn func(models.SupaResult) error) error {
+				for _, r := range parsed {
+					if err := fn(r); err != nil {
+						return err
+					}
+				}
+				return nil
+			}
 		}
 
-		var results map[uuid.UUID]models.SupaResult
-		switch reportType {
-		case "allure":
-			results, err = allure.ReadResults(resultsPath)
-		case "junit":
-			results, err = junit.ReadResults(resultsPath)
-		default:
-			err = fmt.Errorf("only 'junit' and 'allure' types supported")
+		launchID, err := launchForUpload(supa)
+		if err != nil || launchID == 0 {
+			fmt.Printf("error trying to create launch: %v\n", err)
+			os.Exit(1)
 		}
-		if err != nil {
-			fmt.Printf("error trying to parse results folder: %v", err)
-			return
+
+		var uploader *attachments.Uploader
+		if viper.GetBool("attachments.enabled") {
+			uploader = attachments.NewUploader(supa, attachments.Options{
+				Bucket:  viper.GetString("attachments.bucket"),
+				MaxSize: viper.GetInt64("attachments.maxSize") << 20,
+			})
 		}
 
+		// results are uploaded by a bounded pool while the report is still being read
+		results := make(chan models.SupaResult)
+		bar := progress.New(progressWriter(), "uploading results", 0)
 		var wg sync.WaitGroup
-		for _, r := range results {
-			r := r
+		for w := 0; w < workerCount(); w++ {
 			wg.Add(1)
 			go func() {
 				defer wg.Done()
-				r.LaunchID = launchID
-				err := supa.CreateResult(r)
-				if err != nil {
-					fmt.Printf("problems with inserting Result: %s name - %s. %v", r.ID, r.Name, err)
-					return
+				for r := range results {
+					uploadResult(supa, uploader, launchID, r)
+					bar.Add(1)
 				}
 			}()
 		}
+		err = read(func(r models.SupaResult) error {
+			results <- r
+			return nil
+		})
+		close(results)
 		wg.Wait()
+		bar.Finish()
+		if err = checkDiagnostics(err); err != nil {
+			// results read before the error are already uploaded
+			fmt.Printf("error trying to parse results folder: %v\n", err)
+			os.Exit(1)
+		}
+		if uploader != nil {
+			stats := uploader.Stats()
+			fmt.Printf("%d attachments uploaded, %d deduplicated, %d skipped, %d failed\n",
+				stats.Uploaded, stats.Deduplicated, stats.Skipped, stats.Failed)
+		}
 		fmt.Println("upload succeed")
 	},
 }
@@ -98,6 +135,75 @@ func init() {
 		&isTemplate, "isReference", "r", false,
 		"should this run be used as reference (requires admin permission)")
 
+	uploadCmd.Flags().BoolVar(
+		&appendRun, "append", false,
+		"add results to the existing launch with the same name, for ex. from another CI shard")
+
+	uploadCmd.Flags().BoolVar(
+		&withAttachments, "with-attachments", false,
+		"upload attachments of results, for ex. screenshots and logs, to the storage bucket")
+	uploadCmd.Flags().StringVar(
+		&attachmentsBucket, "attachments-bucket", attachments.DefaultBucket,
+		"storage bucket to upload attachments to")
+	uploadCmd.Flags().Int64Var(
+		&attachmentsMaxSize, "attachments-max-size", attachments.DefaultMaxSize>>20,
+		"size limit of a single attachment in megabytes, larger ones are skipped")
+
 	viper.BindPFlag("launch", uploadCmd.Flags().Lookup("launch"))
 	viper.BindPFlag("isReference", uploadCmd.Flags().Lookup("isReference"))
+	viper.BindPFlag("append", uploadCmd.Flags().Lookup("append"))
+	viper.BindPFlag("attachments.enabled", uploadCmd.Flags().Lookup("with-attachments"))
+	viper.BindPFlag("attachments.bucket", uploadCmd.Flags().Lookup("attachments-bucket"))
+	viper.BindPFlag("attachments.maxSize", uploadCmd.Flags().Lookup("attachments-max-size"))
+}
+
+// uploadResult uploads attachments of the result if they are enabled and the result itself.
+func uploadResult(supa supabase.IClient, uploader *attachments.Uploader, launchID int64, r models.SupaResult) {
+	if uploader == nil {
+		// references without uploaded files are useless
+		r.Attachments = nil
+	} else if err := uploader.Upload(&r); err != nil {
+		// the result is inserted anyway, failed attachments are kept without the path
+		fmt.Printf("problems with uploading attachments: %s name - %s. %v\n", r.ID, r.Name, err)
+	}
+	r.LaunchID = launchID
+	if err := supa.CreateResult(r); err != nil {
+		fmt.Printf("problems with inserting Result: %s name - %s. %v", r.ID, r.Name, err)
+	}
+}
+
+// launchForUpload creates a new launch or returns the existing one in append mode.
+// The reference flag is only applied when the launch is created. Shards started at the same time
+// race to create the launch, the ones that lose append to the launch of the winner.
+func launchForUpload(supa supabase.IClient) (int64, error) {
+	appending := viper.GetBool("append")
+	if appending && launch == "" {
+		return 0, fmt.Errorf("launch name is required to append results")
+	}
+	if appending {
+		if id, err := existingLaunch(supa); err != nil || id != 0 {
+			return id, err
+		}
+	}
+	id, err := supa.CreateLaunch(models.Launch{
+		IsTemplate: isTemplate,
+		Name:       launch,
+		VersionID:  int64(versionID),
+	})
+	if appending && errors.Is(err, supabase.ErrLaunchExists) {
+		return existingLaunch(supa)
+	}
+	return id, err
+}
+
+// existingLaunch returns the ID of the launch to append to, 0 if there is no such launch.
+func existingLaunch(supa supabase.IClient) (int64, error) {
+	l, err := supa.GetLaunch(launch)
+	if err != nil || l == nil {
+		return 0, err
+	}
+	if l.VersionID != int64(versionID) {
+		return 0, fmt.Errorf("launch %s belongs to another version, id=%d", launch, l.VersionID)
+	}
+	return *l.ID, nil
 }
@@ -0,0 +1,169 @@
+package cmd
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"test-inspector/internal/supabase"
+	"test-inspector/pkg/models"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+// launchesTable is a stand-in of the launches table with unique names behind the rest API.
+// Every shard checks the name before any of them inserts the launch, like shards started at the same time,
+// then shards take turns from the check to the insert, so every shard sees launches of the previous ones.
+type launchesTable struct {
+	mu       sync.Mutex
+	checked  sync.WaitGroup
+	turn     sync.Mutex
+	launches []models.Launch
+}
+
+func (t *launchesTable) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	switch r.Method {
+	case http.MethodGet:
+		found := t.find(q)
+		if q.Get("select") == "id" {
+			// the name is checked before any shard inserts the launch, the insert waits for the turn
+			t.checked.Done()
+			t.checked.Wait()
+			t.turn.Lock()
+		}
+		json.NewEncoder(w).Encode(found)
+	case http.MethodPost:
+		defer t.turn.Unlock()
+		var l models.Launch
+		json.NewDecoder(r.Body).Decode(&l)
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		for _, known := range t.launches {
+			if known.Name == l.Name {
+				w.WriteHeader(http.StatusConflict)
+				w.Write([]byte(`{"code":"23505","message":"duplicate key value violates unique constraint \"launches_name_key\""}`))
+				return
+			}
+		}
+		id := int64(len(t.launches) + 1)
+		l.ID = &id
+		t.launches = append(t.launches, l)
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode([]models.Launch{l})
+	case http.MethodPatch:
+		var values map[string]bool
+		json.NewDecoder(r.Body).Decode(&values)
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		for i, l := range t.launches {
+			if matches(l, q) {
+				t.launches[i].IsTemplate = values["is_template"]
+			}
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// find returns launches that match filters of the query.
+func (t *launchesTable) find(q url.Values) []models.Launch {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	found := []models.Launch{}
+	for _, l := range t.launches {
+		if matches(l, q) {
+			found = append(found, l)
+		}
+	}
+	return found
+}
+
+// matches checks the launch with eq and neq filters of the query.
+func matches(l models.Launch, q url.Values) bool {
+	columns := map[string]string{
+		"id":          strconv.FormatInt(*l.ID, 10),
+		"name":        l.Name,
+		"is_template": strconv.FormatBool(l.IsTemplate),
+	}
+	for column, value := range columns {
+		filter := q.Get(column)
+		if strings.HasPrefix(filter, "eq.") && filter != "eq."+value ||
+			strings.HasPrefix(filter, "neq.") && filter == "neq."+value {
+			return false
+		}
+	}
+	return true
+}
+
+// templates returns names of launches used as reference.
+func (t *launchesTable) templates() []string {
+	names := []string{}
+	for _, l := range t.launches {
+		if l.IsTemplate {
+			names = append(names, l.Name)
+		}
+	}
+	return names
+}
+
+func TestLaunchForUploadShards(t *testing.T) {
+	tests := []struct {
+		name          string
+		isTemplate    bool
+		wantTemplates string
+	}{
+		{name: "results", wantTemplates: "previous"},
+		{name: "reference", isTemplate: true, wantTemplates: "nightly"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			launch, isTemplate, versionID = "nightly", tt.isTemplate, 3
+			viper.Set("append", true)
+			defer func() {
+				launch, isTemplate, versionID = "", false, 0
+				viper.Set("append", false)
+			}()
+
+			const shards = 8
+			previousID := int64(1)
+			table := &launchesTable{launches: []models.Launch{
+				{ID: &previousID, Name: "previous", IsTemplate: true, VersionID: 3},
+			}}
+			table.checked.Add(shards)
+			server := httptest.NewServer(table)
+			defer server.Close()
+			supa, err := supabase.CreateClient(server.URL, "key", supabase.UserCredentials{})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			ids := make([]int64, shards)
+			errs := make([]error, shards)
+			var wg sync.WaitGroup
+			for i := 0; i < shards; i++ {
+				wg.Add(1)
+				go func(i int) {
+					defer wg.Done()
+					ids[i], errs[i] = launchForUpload(supa)
+				}(i)
+			}
+			wg.Wait()
+
+			if len(table.launches) != 2 {
+				t.Fatalf("%d launches created, want 1", len(table.launches)-1)
+			}
+			for i := range ids {
+				if errs[i] != nil || ids[i] != 2 {
+					t.Errorf("shard %d got launch %d, %v, want launch 2", i, ids[i], errs[i])
+				}
+			}
+			if got := strings.Join(table.templates(), ", "); got != tt.wantTemplates {
+				t.Errorf("templates %s, want %s", got, tt.wantTemplates)
+			}
+		})
+	}
+}
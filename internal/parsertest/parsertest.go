@@ -0,0 +1,157 @@
+// Package parsertest checks results of report parsers read from fixtures in tests of parsers.
+package parsertest
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"test-inspector/pkg/diag"
+	"test-inspector/pkg/models"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+// Results are results of the parser sorted by names.
+type Results []models.SupaResult
+
+// Read reads results of the fixture with the parser and splits diagnostics from fatal errors.
+func Read(t *testing.T, read func(string) (map[uuid.UUID]models.SupaResult, error), path string) (Results, diag.Diagnostics) {
+	t.Helper()
+	results, err := read(path)
+	d, fatal := diag.From(err)
+	if fatal != nil {
+		t.Fatalf("read %s: %v", path, fatal)
+	}
+	res := Results{}
+	for _, r := range results {
+		res = append(res, r)
+	}
+	sort.SliceStable(res, func(i, j int) bool { return res[i].Name < res[j].Name })
+	return res, d
+}
+
+// Find returns the only result with the name.
+func (r Results) Find(t *testing.T, name string) models.SupaResult {
+	t.Helper()
+	found := r.All(name)
+	if len(found) != 1 {
+		t.Fatalf("%d results named %q, want 1 in %v", len(found), name, r.Names())
+	}
+	return found[0]
+}
+
+// All returns results with the name.
+func (r Results) All(name string) []models.SupaResult {
+	found := []models.SupaResult{}
+	for _, res := range r {
+		if res.Name == name {
+			found = append(found, res)
+		}
+	}
+	return found
+}
+
+// Names returns names of results.
+func (r Results) Names() []string {
+	names := make([]string, 0, len(r))
+	for _, res := range r {
+		names = append(names, res.Name)
+	}
+	return names
+}
+
+// Want is the expected mapping of the test, empty fields are not checked.
+// @property {string} Message - A part of the failure message.
+// @property {[]string} Steps - Steps as `name: status`, nested steps are indented by two spaces, see Steps.
+// @property {int16} Attempts - The number of runs of the test.
+type Want struct {
+	FullName    string
+	ParentSuite string
+	Suite       string
+	SubSuite    string
+	Status      string
+	Message     string
+	Steps       []string
+	Attempts    int16
+}
+
+// Check compares the result with the expected mapping.
+func Check(t *testing.T, r models.SupaResult, want Want) {
+	t.Helper()
+	fields := []struct{ name, got, want string }{
+		{"full name", r.FullName, want.FullName},
+		{"parent suite", r.ParentSuite, want.ParentSuite},
+		{"suite", r.Suite, want.Suite},
+		{"sub suite", r.SubSuite, want.SubSuite},
+		{"status", r.Status, want.Status},
+	}
+	for _, f := range fields {
+		if f.want != "" && f.got != f.want {
+			t.Errorf("%s: %s is %q, want %q", r.Name, f.name, f.got, f.want)
+		}
+	}
+	if want.Message != "" && (r.StatusDetails == nil || r.StatusDetails.Message == nil ||
+		!strings.Contains(*r.StatusDetails.Message, want.Message)) {
+		t.Errorf("%s: message %v does not contain %q", r.Name, r.StatusDetails, want.Message)
+	}
+	if want.Steps != nil {
+		if got := Steps(t, r); !reflect.DeepEqual(got, want.Steps) {
+			t.Errorf("%s: steps\n%s\nwant\n%s", r.Name, strings.Join(got, "\n"), strings.Join(want.Steps, "\n"))
+		}
+	}
+	if want.Attempts != 0 && r.Attempts != want.Attempts {
+		t.Errorf("%s: %d attempts, want %d", r.Name, r.Attempts, want.Attempts)
+	}
+}
+
+// Steps returns steps of the result as `name: status`, nested steps are indented by two spaces.
+func Steps(t *testing.T, r models.SupaResult) []string {
+	t.Helper()
+	if r.Steps == "" {
+		return []string{}
+	}
+	var steps []*models.StepContainer
+	if err := json.Unmarshal([]byte(r.Steps), &steps); err != nil {
+		t.Fatalf("%s: steps %s: %v", r.Name, r.Steps, err)
+	}
+	return flatten(steps, "")
+}
+
+func flatten(steps []*models.StepContainer, indent string) []string {
+	res := []string{}
+	for _, s := range steps {
+		res = append(res, indent+s.Name+": "+s.Status)
+		res = append(res, flatten(s.StepContainer, indent+"  ")...)
+	}
+	return res
+}
+
+// Label returns values of labels of the result with the name.
+func Label(r models.SupaResult, name string) []string {
+	values := []string{}
+	for _, l := range r.Labels {
+		if l.Name == name {
+			values = append(values, l.Value)
+		}
+	}
+	return values
+}
+
+// Malformed checks that the only error diagnostic is the one of the file with the kind.
+func Malformed(t *testing.T, d diag.Diagnostics, file, kind string) {
+	t.Helper()
+	if d.Errors() != 1 {
+		t.Fatalf("%d error diagnostics, want 1: %v", d.Errors(), d)
+	}
+	for _, x := range d {
+		if x.Severity != diag.Error {
+			continue
+		}
+		if filepath.Base(x.File) != file || x.Kind != kind {
+			t.Errorf("diagnostic %s is %s, want %s of %s", x, x.Kind, kind, file)
+		}
+	}
+}
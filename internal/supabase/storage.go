@@ -0,0 +1,65 @@
+package supabase
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const (
+	// StorageEndpoint is the endpoint for the Storage API
+	StorageEndpoint = "storage/v1"
+)
+
+type storageError struct {
+	StatusCode string `json:"statusCode"`
+	Error      string `json:"error"`
+	Message    string `json:"message"`
+}
+
+// UploadObject uploads the file to the storage bucket. Objects are never overwritten,
+// the object that already exists is considered uploaded, so objects should be named by their content.
+func (c *Client) UploadObject(bucket, objectPath, contentType string, data []byte) error {
+	segments := strings.Split(objectPath, "/")
+	for i, s := range segments {
+		segments[i] = url.PathEscape(s)
+	}
+	reqURL := fmt.Sprintf("%s/%s/object/%s/%s",
+		c.baseURL, StorageEndpoint, url.PathEscape(bucket), strings.Join(segments, "/"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), c.httpClient.Timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, reqURL, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	injectAuthorizationHeader(req, c.accessToken)
+	req.Header.Set("apikey", c.apiKey)
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("x-upsert", "false")
+
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode >= http.StatusOK && res.StatusCode < 300 {
+		return nil
+	}
+
+	body, _ := io.ReadAll(res.Body)
+	errRes := storageError{}
+	if err = json.Unmarshal(body, &errRes); err != nil {
+		return fmt.Errorf("unknown, status code: %d", res.StatusCode)
+	}
+	// storage responds with 400 and the 409 status code in the body for duplicates
+	if res.StatusCode == http.StatusConflict || errRes.StatusCode == "409" || errRes.Error == "Duplicate" {
+		return nil
+	}
+	return fmt.Errorf("%s: %s", errRes.Error, errRes.Message)
+}
@@ -0,0 +1,58 @@
+package supabase
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestUploadObject(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		wantErr bool
+	}{
+		{name: "uploaded", status: http.StatusOK, body: `{"Key":"attachments/ab/abc.png"}`},
+		{name: "duplicate by status", status: http.StatusConflict,
+			body: `{"statusCode":"409","error":"Duplicate","message":"The resource already exists"}`},
+		{name: "duplicate in body", status: http.StatusBadRequest,
+			body: `{"statusCode":"409","error":"Duplicate","message":"The resource already exists"}`},
+		{name: "bucket not found", status: http.StatusBadRequest,
+			body: `{"statusCode":"404","error":"Bucket not found","message":"Bucket not found"}`, wantErr: true},
+		{name: "server error", status: http.StatusInternalServerError, body: `oops`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got *http.Request
+			var gotBody string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				data, _ := io.ReadAll(r.Body)
+				got, gotBody = r, string(data)
+				w.WriteHeader(tt.status)
+				io.WriteString(w, tt.body)
+			}))
+			defer server.Close()
+			supa, err := CreateClient(server.URL, "key", UserCredentials{})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = supa.UploadObject("attachments", "ab/a b.png", "image/png", []byte("png"))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("UploadObject() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got.Method != http.MethodPost || got.URL.EscapedPath() != "/storage/v1/object/attachments/ab/a%20b.png" {
+				t.Errorf("request %s %s", got.Method, got.URL.EscapedPath())
+			}
+			if got.Header.Get("Content-Type") != "image/png" || got.Header.Get("x-upsert") != "false" ||
+				got.Header.Get("Authorization") != "Bearer key" || got.Header.Get("apikey") != "key" {
+				t.Errorf("request headers %v", got.Header)
+			}
+			if gotBody != "png" {
+				t.Errorf("request body %q, want %q", gotBody, "png")
+			}
+		})
+	}
+}
@@ -3,9 +3,11 @@ package supabase
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 	"test-inspector/internal/supabase/tables"
 	"test-inspector/internal/supabase/tables/launch"
 	"test-inspector/internal/supabase/tables/result"
@@ -20,8 +22,14 @@ import (
 const (
 	// RestEndpoint is the endpoint for the PostgREST API
 	RestEndpoint = "rest/v1"
+	// uniqueViolation is the postgres error code of a duplicate key, postgrest puts it in the error.
+	uniqueViolation = "(23505)"
 )
 
+// ErrLaunchExists is returned by CreateLaunch if the launch with the same name exists,
+// for ex. created by another CI shard at the same time.
+var ErrLaunchExists = errors.New("launch with that name already exists")
+
 // IClient is an interface to communicate with supabase project.
 // @property GetVersion - This is used to get the version of the test that is being run.
 // @property CreateLaunch - Creates a new launch in the database.
@@ -29,12 +37,18 @@ const (
 // created.
 // @property GetTemplate - This is the method that will be called to get the template for the test.
 // @property GetFeatures - Returns a list of features that are available to be tested.
+// @property GetLaunch - Returns the launch by its name.
+// @property GetResults - Returns all results of the launch.
+// @property UploadObject - Uploads the file to the storage bucket.
 type IClient interface {
 	GetVersion(id int32) (int32, error)
 	CreateLaunch(l models.Launch) (int64, error)
+	GetLaunch(name string) (*models.Launch, error)
 	CreateResult(r models.SupaResult) error
 	GetTemplate(versionID int64) ([]models.SupaResult, error)
 	GetFeatures() ([]string, error)
+	GetResults(launchID int64) ([]models.SupaResult, error)
+	UploadObject(bucket, objectPath, contentType string, data []byte) error
 }
 
 // Client is a supabase client struct
@@ -45,14 +59,16 @@ type IClient interface {
 // @property DB - This is a pointer to a postgrest.Client. This is the client that will be used to
 // make requests to the PostgREST API.
 // @property {User} user - The user that is currently logged in.
+// @property {string} accessToken - The access token of the user used for the storage API.
 type Client struct {
 	baseURL string
 	// apiKey can be a client API key or a service key
-	apiKey     string
-	httpClient *http.Client
-	auth       *Auth
-	DB         *postgrest.Client
-	user       User
+	apiKey      string
+	httpClient  *http.Client
+	auth        *Auth
+	DB          *postgrest.Client
+	user        User
+	accessToken string
 }
 
 // CreateClient creates a new Supabase client
@@ -88,6 +104,7 @@ func CreateClient(baseURL string, supabaseKey string, user UserCredentials) (ICl
 		"Authorization": fmt.Sprintf("Bearer %s", details.AccessToken),
 	})
 	client.user = details.User
+	client.accessToken = details.AccessToken
 	return client, nil
 }
 
@@ -128,41 +145,50 @@ func (c *Client) CreateLaunch(l models.Launch) (int64, error) {
 		return 0, err
 	}
 	if len(ids) == 1 {
-		return 0, fmt.Errorf("launch with that name already exists, id=%d", ids[0].ID)
+		return 0, fmt.Errorf("%w, id=%d", ErrLaunchExists, ids[0].ID)
+	}
+	_, err = c.DB.From(tables.Launches.String()).
+		Insert(l, false, "", "representation", "exact").
+		ExecuteTo(&ids)
+	if err != nil {
+		// the launch was created after the check, names are unique
+		if strings.Contains(err.Error(), uniqueViolation) {
+			return 0, fmt.Errorf("%w: %v", ErrLaunchExists, err)
+		}
+		return 0, err
 	}
-	// remove template flag from previous template
+	// remove template flag from previous templates only after the new one is inserted,
+	// so a shard that lost the race for the name does not clear the template of the winner
 	if l.IsTemplate {
-		lastTemplates := []models.Launch{}
 		// todo add filter by project
-		_, err = c.DB.
+		_, _, err = c.DB.
 			From(tables.Launches.String()).
-			Select("*", "1", false).
+			Update(map[string]bool{launch.IsTemplate.String(): false}, "minimal", "").
 			Eq(launch.IsTemplate.String(), "true").
-			ExecuteTo(&lastTemplates)
+			Neq(launch.ID.String(), strconv.FormatInt(ids[0].ID, 10)).
+			Execute()
 		if err != nil {
 			return 0, err
 		}
-		// todo add filter by project and fix all if smth wrong
-		if len(lastTemplates) > 0 {
-			prevTemplate := lastTemplates[0]
-			prevTemplate.IsTemplate = false
-			_, _, err = c.DB.
-				From(tables.Launches.String()).
-				Update(prevTemplate, launch.ID.String(), "1").
-				Eq(launch.ID.String(), strconv.Itoa(int(*prevTemplate.ID))).
-				Execute()
-			if err != nil {
-				return 0, err
-			}
-		}
 	}
-	_, err = c.DB.From(tables.Launches.String()).
-		Insert(l, false, "", "representation", "exact").
-		ExecuteTo(&ids)
+	return ids[0].ID, nil
+}
+
+// GetLaunch getting the launch by its name, it returns nil if there is no such launch.
+func (c *Client) GetLaunch(name string) (*models.Launch, error) {
+	var launches []models.Launch
+	_, err := c.DB.
+		From(tables.Launches.String()).
+		Select("*", "1", false).
+		Eq(launch.Name.String(), name).
+		ExecuteTo(&launches)
 	if err != nil {
-		return 0, err
+		return nil, err
 	}
-	return ids[0].ID, nil
+	if len(launches) == 0 {
+		return nil, nil
+	}
+	return &launches[0], nil
 }
 
 // CreateResult adds a new result in the database.
@@ -243,3 +269,20 @@ func (c *Client) GetFeatures() ([]string, error) {
 
 	return features, nil
 }
+
+// GetResults getting all results of the launch from the database.
+func (c *Client) GetResults(launchID int64) ([]models.SupaResult, error) {
+	var results []models.SupaResult
+	_, err := c.DB.
+		From(tables.Results.String()).
+		Select("*", "", false).
+		Eq(result.LaunchID.String(), strconv.FormatInt(launchID, 10)).
+		ExecuteTo(&results)
+	if err != nil {
+		return nil, err
+	}
+	if len(results) == 0 {
+		return nil, fmt.Errorf("no results found for launch with ID: '%d'", launchID)
+	}
+	return results, nil
+}
@@ -15,6 +15,17 @@ const (
 	Status
 	Description
 	Steps
+	Befores
+	Afters
+	Attempts
+	StatusDetails
+	Parameters
+	Links
+	Stage
+	HistoryID
+	Start
+	Stop
+	Attachments
 	LaunchID
 	Duration
 	CreatedAt
@@ -31,6 +42,17 @@ var results = [...]string{
 	"status",
 	"description",
 	"steps",
+	"befores",
+	"afters",
+	"attempts",
+	"status_details",
+	"parameters",
+	"links",
+	"stage",
+	"history_id",
+	"start",
+	"stop",
+	"attachments",
 	"launch_id",
 	"duration",
 	"created_at",
@@ -0,0 +1,39 @@
+package allure
+
+import (
+	"path/filepath"
+	"test-inspector/pkg/models"
+)
+
+// collectAttachments returns references to attachments of the test and all its steps.
+// Allure writes attachment files next to results, so sources are resolved against the folder of the result.
+func collectAttachments(dir string, r *models.AllureResult) []*models.SupaAttachment {
+	res := []*models.SupaAttachment{}
+	seen := map[string]bool{}
+	add := func(attachments []*models.Attachment) {
+		for _, a := range attachments {
+			if a.Source == "" || seen[a.Source] {
+				continue
+			}
+			seen[a.Source] = true
+			res = append(res, &models.SupaAttachment{
+				Name:   a.Name,
+				Type:   a.Type,
+				Source: filepath.Join(dir, filepath.Base(a.Source)),
+			})
+		}
+	}
+	var walk func(steps []*models.Step)
+	walk = func(steps []*models.Step) {
+		for _, s := range steps {
+			add(s.Attachments)
+			walk(s.Steps)
+		}
+	}
+	add(r.Attachments)
+	walk(r.Steps)
+	if len(res) == 0 {
+		return nil
+	}
+	return res
+}
@@ -0,0 +1,112 @@
+package allure
+
+import (
+	"sort"
+	"test-inspector/pkg/models"
+	"test-inspector/pkg/supatms"
+
+	"github.com/google/uuid"
+)
+
+// containerTree links allure containers with their children,
+// so the suite hierarchy and fixtures of every result can be restored.
+type containerTree struct {
+	parents map[uuid.UUID][]*models.Container
+}
+
+func newContainerTree(containers map[uuid.UUID]*models.Container) *containerTree {
+	tree := &containerTree{
+		parents: map[uuid.UUID][]*models.Container{},
+	}
+	for _, c := range containers {
+		for _, child := range c.Children {
+			tree.parents[child] = append(tree.parents[child], c)
+		}
+	}
+	// map iteration order is random, so keep parents ordered to get stable hierarchy
+	for _, p := range tree.parents {
+		sort.Slice(p, func(i, j int) bool {
+			if p[i].Start != p[j].Start {
+				return p[i].Start < p[j].Start
+			}
+			return p[i].UUID.String() < p[j].UUID.String()
+		})
+	}
+	return tree
+}
+
+// ancestors returns all containers the child belongs to, starting from the root one.
+func (t *containerTree) ancestors(child uuid.UUID) []*models.Container {
+	res := []*models.Container{}
+	visited := map[uuid.UUID]bool{}
+	var visit func(id uuid.UUID)
+	visit = func(id uuid.UUID) {
+		for _, p := range t.parents[id] {
+			if visited[p.UUID] {
+				continue
+			}
+			visited[p.UUID] = true
+			visit(p.UUID)
+			res = append(res, p)
+		}
+	}
+	visit(child)
+	return res
+}
+
+// applySuiteLabels fills suite labels of the result with the ones derived from its containers
+// if the result does not have them.
+func applySuiteLabels(r *models.AllureResult, ancestors []*models.Container) {
+	for _, name := range []string{"parentSuite", "suite", "subSuite", "feature"} {
+		if _, ok := r.FindLabel(name); ok {
+			continue
+		}
+		for i := len(ancestors) - 1; i >= 0; i-- {
+			if value, ok := ancestors[i].FindLabel(name); ok {
+				r.Labels = append(r.Labels, &models.Label{Name: name, Value: value})
+				break
+			}
+		}
+	}
+	if hasSuiteLabels(r) {
+		return
+	}
+
+	names := []string{}
+	for _, c := range ancestors {
+		if c.Name == "" || c.Name == r.Name || (r.FullName != nil && c.Name == *r.FullName) {
+			continue
+		}
+		names = append(names, c.Name)
+	}
+	r.Labels = append(r.Labels, supatms.SuiteLabels(names)...)
+}
+
+func hasSuiteLabels(r *models.AllureResult) bool {
+	for _, name := range []string{"parentSuite", "suite", "subSuite"} {
+		if _, ok := r.FindLabel(name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// fixtures returns befores and afters of the containers as JSON strings of steps.
+// Befores are ordered from the root container, afters - from the closest one.
+func fixtures(ancestors []*models.Container) (string, string, error) {
+	befores := &models.Step{}
+	afters := &models.Step{}
+	for i, c := range ancestors {
+		befores.Steps = append(befores.Steps, c.Befores...)
+		afters.Steps = append(afters.Steps, ancestors[len(ancestors)-1-i].Afters...)
+	}
+	beforesRaw, err := supatms.StepsJSON(befores)
+	if err != nil {
+		return "", "", err
+	}
+	aftersRaw, err := supatms.StepsJSON(afters)
+	if err != nil {
+		return "", "", err
+	}
+	return beforesRaw, aftersRaw, nil
+}
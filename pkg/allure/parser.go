@@ -3,110 +3,195 @@ package allure
 import (
 	"encoding/json"
 	"fmt"
-
-	// nolint:depguard // we need to import this package to read result files
-	"io/ioutil"
-	"os"
+	"io"
 	"path/filepath"
 	"strings"
 	"sync"
+	"test-inspector/pkg/diag"
+	"test-inspector/pkg/files"
 	"test-inspector/pkg/models"
+	"test-inspector/pkg/progress"
 	"test-inspector/pkg/supatms"
+	"test-inspector/pkg/workers"
 
 	"github.com/google/uuid"
 )
 
-// ReadResults reads all files from the allure results folder,
+// Parser is a report parser for allure results folders.
+// @property {int} Workers - The number of files read at the same time, the number of CPUs by default.
+// @property Progress - Where to print the progress of reading, nothing is printed if it is nil.
+type Parser struct {
+	Workers  int
+	Progress io.Writer
+}
+
+// Name returns the report type of allure results.
+func (Parser) Name() string {
+	return "allure"
+}
+
+// Detect checks if the folder or the archive contains allure `*-result.json` or `*-container.json` files.
+func (Parser) Detect(resultsPath string) bool {
+	if !files.IsDir(resultsPath) {
+		return false
+	}
+	reports, err := files.List(resultsPath, ".json")
+	if err != nil {
+		return false
+	}
+	for _, f := range reports {
+		name := filepath.Base(f)
+		if result.Matches(name) || container.Matches(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// ReadResults reads allure results folder, see StreamResults.
+func (p Parser) ReadResults(resultsPath string) (map[uuid.UUID]models.SupaResult, error) {
+	results := map[uuid.UUID]models.SupaResult{}
+	err := p.StreamResults(resultsPath, func(r models.SupaResult) error {
+		results[r.ID] = r
+		return nil
+	})
+	return results, err
+}
+
+// ReadResults reads all files from the allure results folder or archive,
 // parses them and returns a map of SupaResults
 func ReadResults(resultsPath string) (map[uuid.UUID]models.SupaResult, error) {
-	files, err := ioutil.ReadDir(resultsPath)
+	return Parser{}.ReadResults(resultsPath)
+}
+
+// StreamResults reads all files from the allure results folder or archive and hands off results
+// to fn one by one, fn is never called concurrently. Malformed files are skipped and returned
+// as diag.Diagnostics. Files are read twice by a bounded pool of workers:
+// the first pass reads containers and indexes attempts of tests, the second one decodes final attempts,
+// so only containers and the index are kept in memory for huge results folders.
+func (p Parser) StreamResults(resultsPath string, fn func(models.SupaResult) error) error {
+	reports, err := files.List(resultsPath, ".json")
 	if err != nil {
-		return nil, fmt.Errorf("error trying to read files from dir: %v", err)
+		return fmt.Errorf("error trying to read files from dir: %v", err)
 	}
 
-	results := map[uuid.UUID]models.SupaResult{}
+	found := []*attempt{}
+	containers := map[uuid.UUID]*models.Container{}
+	diagnostics := &diag.Collector{}
 	var mu sync.Mutex
-	var wg sync.WaitGroup
-	for _, f := range files {
-		f := f
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
-			switch {
-			case f.IsDir():
-				// allure does not create inner folders
+	bar := progress.New(p.Progress, "reading allure results", len(reports))
+	workers.Run(p.Workers, len(reports), func(i int) {
+		defer bar.Add(1)
+		f := reports[i]
+		name := filepath.Base(f)
+		switch {
+		case attachment.Matches(name):
+			// attachments are read on upload by references of results
+			return
+		case container.Matches(name):
+			c := &models.Container{}
+			if err := decodeFile(f, c); err != nil {
+				diagnostics.Add(diag.Skip(f, err))
 				return
-			case strings.Contains(f.Name(), attachment.String()):
-				// we don't need to upload attachments
+			}
+			mu.Lock()
+			containers[c.UUID] = c
+			mu.Unlock()
+		case result.Matches(name):
+			a := &attempt{path: f}
+			if err := decodeFile(f, a); err != nil {
+				diagnostics.Add(diag.Skip(f, err))
+				return
+			}
+			if a.UUID == uuid.Nil || a.Name == "" {
+				// for ex. a json report of another tool with a similar name
+				diagnostics.Add(diag.Diagnostic{File: f, Kind: diag.Incomplete,
+					Reason: "result without uuid or name", Severity: diag.Error})
 				return
-			case strings.Contains(f.Name(), container.String()):
-				// suites provided by allure container json are not supported
+			}
+			mu.Lock()
+			found = append(found, a)
+			mu.Unlock()
+		}
+	})
+	bar.Finish()
+
+	tree := newContainerTree(containers)
+	finals := collapseRetries(found)
+	bar = progress.New(p.Progress, "converting allure results", len(finals))
+	defer bar.Finish()
+
+	results := make(chan models.SupaResult)
+	stop := make(chan struct{})
+	go func() {
+		defer close(results)
+		workers.Run(p.Workers, len(finals), func(i int) {
+			defer bar.Add(1)
+			res, warnings, err := convertResult(finals[i], tree)
+			diagnostics.Add(warnings...)
+			if err != nil {
+				diagnostics.Add(diag.Skip(finals[i].last.path, err))
 				return
-			case strings.Contains(f.Name(), result.String()):
-				{
-					res, err := parseResult(resultsPath, f)
-					if err != nil {
-						fmt.Printf("error trying to parse result %s: %v", f.Name(), err)
-						return
-					}
-					steps := ""
-					if res.Steps != nil && len(res.Steps) > 0 {
-						stepsStruct := parseSteps(res)
-						stepsRaw, err := json.Marshal(stepsStruct)
-						if err != nil {
-							fmt.Printf("problems with parsing steps: %s name - %s. %v", res.UUID, res.Name, err)
-						}
-						steps = string(stepsRaw)
-					}
-					result := supatms.ToResult(0, *res, steps)
-					mu.Lock()
-					results[result.ID] = result
-					mu.Unlock()
-				}
 			}
-		}()
+			select {
+			case results <- res:
+			case <-stop:
+			}
+		})
+	}()
+	for r := range results {
+		if err = fn(r); err != nil {
+			close(stop)
+			// let the workers finish, they drop results once stopped
+			for range results {
+			}
+			return err
+		}
 	}
-	wg.Wait()
-	return results, nil
+	return diagnostics.Err()
 }
 
-func parseResult(resultsPath string, f os.FileInfo) (*models.AllureResult, error) {
-	var res models.AllureResult
-	jsonFile, err := os.ReadFile(filepath.Join(resultsPath, f.Name()))
+// convertResult reads the final attempt of the test and converts it to SupaResult
+// with suite labels and fixtures of its containers. Steps and fixtures that could not be read
+// are returned as warnings.
+func convertResult(a *attempts, tree *containerTree) (models.SupaResult, []diag.Diagnostic, error) {
+	res := &models.AllureResult{}
+	if err := decodeFile(a.last.path, res); err != nil {
+		return models.SupaResult{}, nil, err
+	}
+	warnings := []diag.Diagnostic{}
+	if a.flaky {
+		if res.StatusDetails == nil {
+			res.StatusDetails = &models.StatusDetails{}
+		}
+		res.StatusDetails.Flaky = true
+	}
+	ancestors := tree.ancestors(res.UUID)
+	applySuiteLabels(res, ancestors)
+	steps, err := supatms.StepsJSON(res)
 	if err != nil {
-		return nil, err
+		warnings = append(warnings, diag.Warn(a.last.path, diag.Unsupported,
+			fmt.Sprintf("steps of %s could not be read: %v", res.Name, err)))
 	}
-	if err = json.Unmarshal(jsonFile, &res); err != nil {
-		return nil, err
+	result := supatms.ToResult(0, *res, steps)
+	result.Attempts = int16(a.count)
+	result.Attachments = collectAttachments(filepath.Dir(a.last.path), res)
+	result.Befores, result.Afters, err = fixtures(ancestors)
+	if err != nil {
+		warnings = append(warnings, diag.Warn(a.last.path, diag.Unsupported,
+			fmt.Sprintf("fixtures of %s could not be read: %v", res.Name, err)))
 	}
-
-	return &res, nil
+	return result, warnings, nil
 }
 
-func parseSteps(r models.AllureStepContainer) []*models.StepContainer {
-	steps := []*models.StepContainer{}
-	var ctr int16
-	for _, s := range r.GetSteps() {
-		if s.Status == nil {
-			s.Status = stringRef("undefined")
-		}
-		stepInfo := models.StepContainer{
-			StepContainer: []*models.StepContainer{},
-			Name:          s.Name,
-			Status:        *s.Status,
-			Position:      ctr,
-		}
-
-		stepInfo.StepContainer = parseSteps(s)
-
-		steps = append(steps, &stepInfo)
-		ctr++
+// decodeFile decodes the json file without reading it into memory first.
+func decodeFile(path string, v interface{}) error {
+	f, err := files.Open(path)
+	if err != nil {
+		return err
 	}
-	return steps
-}
-
-func stringRef(s string) *string {
-	return &s
+	defer f.Close()
+	return json.NewDecoder(f).Decode(v)
 }
 
 // Suffix is used to get the type of allure result file
@@ -130,3 +215,8 @@ func (s Suffix) String() string {
 	}
 	return ""
 }
+
+// Matches checks if the name of the file is `<uuid>-<suffix>.json`, like allure writes them.
+func (s Suffix) Matches(name string) bool {
+	return s.String() != "" && strings.HasSuffix(name, "-"+s.String()+".json")
+}
@@ -0,0 +1,81 @@
+package allure
+
+import (
+	"strings"
+	"test-inspector/internal/parsertest"
+	"test-inspector/pkg/diag"
+	"testing"
+	"time"
+)
+
+func TestReadResults(t *testing.T) {
+	start := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC).UnixMilli()
+	tests := []struct {
+		name, param string
+		want        parsertest.Want
+		flaky       bool
+		stop        int64
+	}{
+		{
+			// retries share the history id, the last attempt passed after failed ones
+			name: "signs in",
+			want: parsertest.Want{
+				FullName: "auth.SignInTest.signsIn", Suite: "Auth", Status: "passed",
+				Steps:    []string{"open the page: passed", "sign in: passed", "  fill the form: passed"},
+				Attempts: 3,
+			},
+			flaky: true, stop: start + 2600,
+		},
+		{
+			// retries without history id are grouped by the full name and parameters
+			name: "uploads", param: "1",
+			want: parsertest.Want{
+				FullName: "storage.UploadTest.uploads", ParentSuite: "storage", Suite: "UploadTest",
+				Status: "failed", Message: "disk full", Attempts: 2,
+			},
+			stop: start + 300,
+		},
+		{
+			name: "uploads", param: "2",
+			want: parsertest.Want{Suite: "UploadTest", Status: "passed", Attempts: 1},
+			stop: start + 100,
+		},
+	}
+	results, d := parsertest.Read(t, ReadResults, "testdata")
+	parsertest.Malformed(t, d, "55555555-0000-0000-0000-000000000001-result.json", diag.Truncated)
+	if len(results) != len(tests)+2 {
+		t.Fatalf("results %v, want %d", results.Names(), len(tests)+2)
+	}
+	for _, tt := range tests {
+		t.Run(tt.name+tt.param, func(t *testing.T) {
+			for _, r := range results.All(tt.name) {
+				if tt.param != "" && r.Parameters[0].Value != tt.param {
+					continue
+				}
+				parsertest.Check(t, r, tt.want)
+				if flaky := r.StatusDetails != nil && r.StatusDetails.Flaky; flaky != tt.flaky {
+					t.Errorf("flaky %v, want %v", flaky, tt.flaky)
+				}
+				if r.Stop != tt.stop {
+					t.Errorf("stop %d, want %d of the last attempt", r.Stop, tt.stop)
+				}
+				return
+			}
+			t.Errorf("no result with parameter %q", tt.param)
+		})
+	}
+
+	// fixtures of the container are kept for the final attempt
+	if r := results.Find(t, "signs in"); !strings.Contains(r.Befores, "start the server") {
+		t.Errorf("befores %s, want the fixture of the container", r.Befores)
+	}
+	// results without history id and full name are never grouped
+	for _, r := range results.All("anonymous") {
+		if r.Attempts != 1 {
+			t.Errorf("anonymous: %d attempts, want 1", r.Attempts)
+		}
+	}
+	if n := len(results.All("anonymous")); n != 2 {
+		t.Errorf("%d anonymous results, want 2", n)
+	}
+}
@@ -0,0 +1,94 @@
+package allure
+
+import (
+	"sort"
+	"strings"
+	"test-inspector/pkg/models"
+
+	"github.com/google/uuid"
+)
+
+// attempt is a single run of the test in the results folder. Only the fields needed
+// to find the final attempt are decoded, the full result is read again for final attempts only.
+type attempt struct {
+	path string
+
+	UUID       uuid.UUID           `json:"uuid"`
+	Name       string              `json:"name"`
+	HistoryID  *string             `json:"historyId,omitempty"`
+	FullName   *string             `json:"fullName,omitempty"`
+	Parameters []*models.Parameter `json:"parameters,omitempty"`
+	Status     string              `json:"status"`
+	Start      int64               `json:"start"`
+	Stop       int64               `json:"stop"`
+}
+
+// attempts is the final attempt of the test with the number of all its attempts.
+// The final attempt is flaky if it passed and one of the earlier attempts failed.
+type attempts struct {
+	last  *attempt
+	count int
+	flaky bool
+}
+
+// collapseRetries groups retries of the same test and keeps only the final attempt of every test.
+func collapseRetries(results []*attempt) []*attempts {
+	groups := map[string][]*attempt{}
+	keys := []string{}
+	for _, r := range results {
+		key := retryKey(r)
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], r)
+	}
+	sort.Strings(keys)
+
+	res := []*attempts{}
+	for _, key := range keys {
+		group := groups[key]
+		sort.Slice(group, func(i, j int) bool {
+			if group[i].Stop != group[j].Stop {
+				return group[i].Stop < group[j].Stop
+			}
+			if group[i].Start != group[j].Start {
+				return group[i].Start < group[j].Start
+			}
+			return group[i].UUID.String() < group[j].UUID.String()
+		})
+		last := group[len(group)-1]
+		res = append(res, &attempts{
+			last:  last,
+			count: len(group),
+			flaky: last.Status == "passed" && hasFailedAttempt(group[:len(group)-1]),
+		})
+	}
+	return res
+}
+
+// retryKey returns the history id of the test, all retries of the test share it.
+// Results without history id are grouped by the full name and parameters,
+// and results without both are never grouped.
+func retryKey(r *attempt) string {
+	if r.HistoryID != nil && *r.HistoryID != "" {
+		return "history:" + *r.HistoryID
+	}
+	if r.FullName == nil || *r.FullName == "" {
+		return "uuid:" + r.UUID.String()
+	}
+	params := make([]string, 0, len(r.Parameters))
+	for _, p := range r.Parameters {
+		params = append(params, p.Name+"="+p.Value)
+	}
+	sort.Strings(params)
+	return "name:" + *r.FullName + "(" + strings.Join(params, ",") + ")"
+}
+
+func hasFailedAttempt(results []*attempt) bool {
+	for _, r := range results {
+		if r.Status == "failed" || r.Status == "broken" {
+			return true
+		}
+	}
+	return false
+}
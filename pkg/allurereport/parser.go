@@ -0,0 +1,283 @@
+// Package allurereport reads the generated allure report, the `allure-report` folder,
+// for teams that only keep the report and not raw allure results.
+package allurereport
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"strings"
+	"test-inspector/pkg/diag"
+	"test-inspector/pkg/files"
+	"test-inspector/pkg/models"
+	"test-inspector/pkg/supatms"
+
+	"github.com/google/uuid"
+)
+
+// testCasesDir is the folder of test cases in the generated report.
+const testCasesDir = "/data/test-cases/"
+
+// Parser is a report parser for generated allure reports.
+type Parser struct{}
+
+// Name returns the report type of generated allure reports.
+func (Parser) Name() string {
+	return "allure-report"
+}
+
+// Detect checks if the folder or the archive contains test cases of the generated allure report.
+func (Parser) Detect(resultsPath string) bool {
+	if !files.IsDir(resultsPath) {
+		return false
+	}
+	reports, err := files.List(resultsPath, ".json")
+	if err != nil {
+		return false
+	}
+	for _, f := range reports {
+		if _, ok := reportRoot(f); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// ReadResults reads generated allure report, see ReadResults.
+func (Parser) ReadResults(resultsPath string) (map[uuid.UUID]models.SupaResult, error) {
+	return ReadResults(resultsPath)
+}
+
+// ReadResults reads `data/test-cases` of the generated allure report folder or archive
+// and returns the final attempt of every test as a SupaResult. Suites of tests without suite labels
+// are taken from `data/suites.json`.
+func ReadResults(resultsPath string) (map[uuid.UUID]models.SupaResult, error) {
+	reports, err := files.List(resultsPath, ".json")
+	if err != nil {
+		return nil, err
+	}
+
+	results := map[uuid.UUID]models.SupaResult{}
+	diagnostics := &diag.Collector{}
+	trees := map[string]map[string][]string{}
+	for _, f := range reports {
+		root, ok := reportRoot(f)
+		if !ok {
+			continue
+		}
+		suites, ok := trees[root]
+		if !ok {
+			if suites, err = readSuites(root); err != nil {
+				diagnostics.Add(diag.Warn(suitesPath(root), diag.Unsupported,
+					fmt.Sprintf("suites could not be read: %v", err)))
+			}
+			trees[root] = suites
+		}
+
+		tc := &models.AllureReportTestCase{}
+		if err := decodeFile(f, tc); err != nil {
+			diagnostics.Add(diag.Skip(f, err))
+			continue
+		}
+		// retries are hidden test cases, the visible one is the final attempt
+		if tc.Hidden || tc.Retry {
+			continue
+		}
+
+		ar := convertTestCase(tc, suites[tc.UID])
+		steps, err := supatms.StepsJSON(ar)
+		if err != nil {
+			diagnostics.Add(diag.Warn(f, diag.Unsupported,
+				fmt.Sprintf("steps of %s could not be read: %v", ar.Name, err)))
+		}
+		res := supatms.ToResult(0, *ar, steps)
+		res.Attempts = int16(tc.RetriesCount + 1)
+		res.Attachments = collectAttachments(root, tc)
+		if res.Befores, res.Afters, err = fixtures(tc); err != nil {
+			diagnostics.Add(diag.Warn(f, diag.Unsupported,
+				fmt.Sprintf("fixtures of %s could not be read: %v", ar.Name, err)))
+		}
+		results[res.ID] = res
+	}
+	return results, diagnostics.Err()
+}
+
+// reportRoot returns the folder of the generated report if the file is its test case.
+func reportRoot(p string) (string, bool) {
+	slash := "/" + filepath.ToSlash(p)
+	i := strings.LastIndex(slash, testCasesDir)
+	if i < 0 || strings.Contains(slash[i+len(testCasesDir):], "/") {
+		return "", false
+	}
+	return filepath.FromSlash(strings.TrimPrefix(slash[:i], "/")), true
+}
+
+func suitesPath(root string) string {
+	return filepath.Join(root, "data", "suites.json")
+}
+
+// readSuites reads the tree of suites of the report and returns names of suites
+// of every test case by its UID. Reports without the tree have no suites.
+func readSuites(root string) (map[string][]string, error) {
+	tree := &models.AllureReportTree{}
+	if err := decodeFile(suitesPath(root), tree); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return map[string][]string{}, nil
+		}
+		return map[string][]string{}, err
+	}
+	suites := map[string][]string{}
+	var walk func(node *models.AllureReportTree, names []string)
+	walk = func(node *models.AllureReportTree, names []string) {
+		for _, child := range node.Children {
+			if len(child.Children) == 0 {
+				suites[child.UID] = names
+				continue
+			}
+			walk(child, append(names[:len(names):len(names)], child.Name))
+		}
+	}
+	walk(tree, []string{})
+	return suites, nil
+}
+
+func convertTestCase(tc *models.AllureReportTestCase, suites []string) *models.AllureResult {
+	ar := &models.AllureResult{
+		Name:          tc.Name,
+		Status:        tc.Status,
+		StatusDetails: statusDetails(tc.StatusMessage, tc.StatusTrace, tc.Flaky),
+		Parameters:    tc.Parameters,
+		Start:         tc.Time.Start,
+		Stop:          tc.Time.Stop,
+		UUID:          uuid.New(),
+		FullName:      &tc.FullName,
+		Labels:        tc.Labels,
+		Links:         tc.Links,
+	}
+	if tc.HistoryID != "" {
+		ar.HistoryID = &tc.HistoryID
+	}
+	if tc.Description != "" {
+		ar.Description = &tc.Description
+	}
+	if tc.DescriptionHTML != "" {
+		ar.DescriptionHTML = &tc.DescriptionHTML
+	}
+	if tc.TestStage != nil {
+		ar.Steps = convertSteps(tc.TestStage.Steps)
+		ar.Attachments = convertAttachments(tc.TestStage.Attachments)
+	}
+	if !hasSuiteLabels(ar) {
+		ar.Labels = append(ar.Labels, supatms.SuiteLabels(suites)...)
+	}
+	return ar
+}
+
+func hasSuiteLabels(r *models.AllureResult) bool {
+	for _, name := range []string{"parentSuite", "suite", "subSuite"} {
+		if _, ok := r.FindLabel(name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func convertSteps(steps []*models.AllureReportStep) []*models.Step {
+	res := make([]*models.Step, 0, len(steps))
+	for _, s := range steps {
+		status := s.Status
+		res = append(res, &models.Step{
+			Name:          s.Name,
+			Status:        &status,
+			StatusDetails: statusDetails(s.StatusMessage, s.StatusTrace, false),
+			Steps:         convertSteps(s.Steps),
+			Attachments:   convertAttachments(s.Attachments),
+			Parameters:    s.Parameters,
+			Start:         s.Time.Start,
+			Stop:          s.Time.Stop,
+		})
+	}
+	return res
+}
+
+func convertAttachments(attachments []*models.AllureReportAttachment) []*models.Attachment {
+	res := make([]*models.Attachment, 0, len(attachments))
+	for _, a := range attachments {
+		res = append(res, &models.Attachment{Name: a.Name, Source: a.Source, Type: a.Type})
+	}
+	return res
+}
+
+func statusDetails(message, trace string, flaky bool) *models.StatusDetails {
+	if message == "" && trace == "" && !flaky {
+		return nil
+	}
+	details := &models.StatusDetails{Flaky: flaky}
+	if message != "" {
+		details.Message = &message
+	}
+	if trace != "" {
+		details.Trace = &trace
+	}
+	return details
+}
+
+// fixtures returns before and after stages of the test case as JSON strings of steps.
+func fixtures(tc *models.AllureReportTestCase) (string, string, error) {
+	befores, err := supatms.StepsJSON(&models.Step{Steps: convertSteps(tc.BeforeStages)})
+	if err != nil {
+		return "", "", err
+	}
+	afters, err := supatms.StepsJSON(&models.Step{Steps: convertSteps(tc.AfterStages)})
+	if err != nil {
+		return "", "", err
+	}
+	return befores, afters, nil
+}
+
+// collectAttachments returns references to attachments of the test case, its steps and fixtures.
+// The report keeps attachment files in `data/attachments`.
+func collectAttachments(root string, tc *models.AllureReportTestCase) []*models.SupaAttachment {
+	res := []*models.SupaAttachment{}
+	seen := map[string]bool{}
+	var walk func(steps []*models.AllureReportStep)
+	walk = func(steps []*models.AllureReportStep) {
+		for _, s := range steps {
+			if s == nil {
+				continue
+			}
+			for _, a := range s.Attachments {
+				if a.Source == "" || seen[a.Source] {
+					continue
+				}
+				seen[a.Source] = true
+				res = append(res, &models.SupaAttachment{
+					Name:   a.Name,
+					Type:   a.Type,
+					Source: filepath.Join(root, "data", "attachments", filepath.Base(a.Source)),
+					Size:   a.Size,
+				})
+			}
+			walk(s.Steps)
+		}
+	}
+	walk(tc.BeforeStages)
+	walk([]*models.AllureReportStep{tc.TestStage})
+	walk(tc.AfterStages)
+	if len(res) == 0 {
+		return nil
+	}
+	return res
+}
+
+// decodeFile decodes the json file without reading it into memory first.
+func decodeFile(path string, v interface{}) error {
+	f, err := files.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return json.NewDecoder(f).Decode(v)
+}
@@ -0,0 +1,93 @@
+package allurereport
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"test-inspector/internal/parsertest"
+	"test-inspector/pkg/diag"
+	"testing"
+	"time"
+)
+
+func TestReadResults(t *testing.T) {
+	start := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC).UnixMilli()
+	tests := []struct {
+		name        string
+		want        parsertest.Want
+		flaky       bool
+		start, stop int64
+	}{
+		{
+			// suites of tests without suite labels are taken from the tree of suites,
+			// retries are hidden test cases and only counted
+			name: "signs in",
+			want: parsertest.Want{
+				FullName: "auth.SignInTest.signsIn", ParentSuite: "Auth", Suite: "sign in", Status: "passed",
+				Steps:    []string{"open the page: passed", "sign in: passed", "  fill the form: passed"},
+				Attempts: 3,
+			},
+			flaky: true, start: start + 2000, stop: start + 2600,
+		},
+		{
+			name: "uploads",
+			want: parsertest.Want{
+				FullName: "storage.UploadTest.uploads", ParentSuite: "storage", Suite: "UploadTest",
+				Status: "broken", Message: "connection reset", Steps: []string{"send the file: broken"},
+				Attempts: 1,
+			},
+			start: start, stop: start + 300,
+		},
+	}
+	results, d := parsertest.Read(t, ReadResults, "testdata")
+	parsertest.Malformed(t, d, "tc-cut.json", diag.Truncated)
+	if len(results) != len(tests) {
+		t.Fatalf("results %v, want %d", results.Names(), len(tests))
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := results.Find(t, tt.name)
+			parsertest.Check(t, r, tt.want)
+			if flaky := r.StatusDetails != nil && r.StatusDetails.Flaky; flaky != tt.flaky {
+				t.Errorf("flaky %v, want %v", flaky, tt.flaky)
+			}
+			if r.Start != tt.start || r.Stop != tt.stop {
+				t.Errorf("start %d, stop %d, want %d, %d", r.Start, r.Stop, tt.start, tt.stop)
+			}
+		})
+	}
+
+	r := results.Find(t, "signs in")
+	if tags := parsertest.Label(r, "tag"); !reflect.DeepEqual(tags, []string{"smoke"}) {
+		t.Errorf("tags %v, want labels of the test case", tags)
+	}
+	if len(r.Parameters) != 1 || r.Parameters[0].Value != "chromium" || r.HistoryID == nil || *r.HistoryID != "h-sign-in" {
+		t.Errorf("parameters %v, history id %v, want ones of the test case", r.Parameters, r.HistoryID)
+	}
+	if !strings.Contains(r.Befores, "start the server") {
+		t.Errorf("befores %s, want the before stage", r.Befores)
+	}
+	// attachments are kept in the data folder of the report
+	want := filepath.Join("testdata", "allure-report", "data", "attachments", "a1-attachment.png")
+	if len(r.Attachments) != 1 || r.Attachments[0].Source != want {
+		t.Errorf("attachments %v, want %s", r.Attachments, want)
+	}
+}
+
+func TestReportRoot(t *testing.T) {
+	tests := []struct {
+		path, root string
+		ok         bool
+	}{
+		{"allure-report/data/test-cases/a.json", "allure-report", true},
+		{"report.zip/allure-report/data/test-cases/a.json", filepath.Join("report.zip", "allure-report"), true},
+		{"data/test-cases/a.json", "", true},
+		{"allure-report/data/suites.json", "", false},
+		{"allure-report/data/test-cases/nested/a.json", "", false},
+	}
+	for _, tt := range tests {
+		if root, ok := reportRoot(tt.path); root != tt.root || ok != tt.ok {
+			t.Errorf("reportRoot(%q) = %q, %v, want %q, %v", tt.path, root, ok, tt.root, tt.ok)
+		}
+	}
+}
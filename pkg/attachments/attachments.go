@@ -0,0 +1,233 @@
+// Package attachments uploads attachment files of results to the storage bucket.
+// Files are named by the hash of their content, so every file is uploaded once
+// even if it is attached to many results or uploaded by many runs.
+package attachments
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"sync"
+	"test-inspector/pkg/files"
+	"test-inspector/pkg/models"
+)
+
+// DefaultBucket is the storage bucket attachments are uploaded to by default.
+const DefaultBucket = "attachments"
+
+// DefaultMaxSize is the size limit of a single attachment in bytes by default.
+const DefaultMaxSize = 10 << 20
+
+// Storage is an interface to upload files to the storage bucket, it is implemented by the supabase client.
+// @property UploadObject - Uploads the file, the object that already exists should not be an error.
+type Storage interface {
+	UploadObject(bucket, objectPath, contentType string, data []byte) error
+}
+
+// Options of the upload.
+// @property {string} Bucket - The name of the storage bucket.
+// @property {int64} MaxSize - The size limit of a single attachment in bytes, larger ones are skipped.
+type Options struct {
+	Bucket  string
+	MaxSize int64
+}
+
+// Stats is the summary of the upload.
+// @property {int} Uploaded - The number of uploaded files.
+// @property {int} Deduplicated - The number of references to files already uploaded in this run.
+// @property {int} Skipped - The number of attachments that are missing or too large.
+// @property {int} Failed - The number of attachments that could not be uploaded to the storage.
+type Stats struct {
+	Uploaded     int
+	Deduplicated int
+	Skipped      int
+	Failed       int
+}
+
+// Uploader uploads attachments of results, it is safe for concurrent use.
+// Every file is read and uploaded once, later references get the path of the first upload.
+type Uploader struct {
+	storage Storage
+	opts    Options
+
+	mu      sync.Mutex
+	sources map[string]*upload
+	objects map[string]*upload
+	stats   Stats
+}
+
+// upload is the file uploaded once, the path is empty for skipped files.
+type upload struct {
+	once        sync.Once
+	path        string
+	contentType string
+	size        int64
+	err         error
+}
+
+// NewUploader returns the uploader to the storage with default options filled.
+func NewUploader(storage Storage, opts Options) *Uploader {
+	if opts.Bucket == "" {
+		opts.Bucket = DefaultBucket
+	}
+	if opts.MaxSize <= 0 {
+		opts.MaxSize = DefaultMaxSize
+	}
+	return &Uploader{
+		storage: storage,
+		opts:    opts,
+		sources: map[string]*upload{},
+		objects: map[string]*upload{},
+	}
+}
+
+// Upload uploads attachments of the result and sets storage paths of its references.
+// Attachments that are missing or too large are kept without the path and reported,
+// attachments that failed to upload are kept without the path too and returned as the error,
+// so the upload of results is not stopped by them.
+func (u *Uploader) Upload(r *models.SupaResult) error {
+	failed := []string{}
+	for _, a := range r.Attachments {
+		file, first := u.once(u.sources, a.Source, func() (*upload, error) { return u.uploadFile(a) })
+		if file.err != nil {
+			u.count(&u.stats.Failed)
+			failed = append(failed, fmt.Sprintf("%s: %v", a.Source, file.err))
+			continue
+		}
+		a.Size = file.size
+		if file.path == "" {
+			continue
+		}
+		a.Path, a.Type = file.path, file.contentType
+		if !first {
+			u.count(&u.stats.Deduplicated)
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("error trying to upload attachments %s", strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+// Stats returns the summary of uploads so far.
+func (u *Uploader) Stats() Stats {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return u.stats
+}
+
+// once runs fn for the key once and returns its result, the flag is true for the call that ran fn.
+func (u *Uploader) once(uploads map[string]*upload, key string, fn func() (*upload, error)) (*upload, bool) {
+	u.mu.Lock()
+	file, ok := uploads[key]
+	if !ok {
+		file = &upload{}
+		uploads[key] = file
+	}
+	u.mu.Unlock()
+
+	first := false
+	file.once.Do(func() {
+		first = true
+		res, err := fn()
+		if err != nil {
+			file.err = err
+			return
+		}
+		file.path, file.contentType, file.size = res.path, res.contentType, res.size
+	})
+	return file, first
+}
+
+// uploadFile reads and uploads the file. Large files are skipped by their size before they are read,
+// and the file is read up to the limit in case it grows in the meantime.
+func (u *Uploader) uploadFile(a *models.SupaAttachment) (*upload, error) {
+	info, err := files.Stat(a.Source)
+	if err != nil {
+		fmt.Printf("skipping attachment %s: %v\n", a.Source, err)
+		u.count(&u.stats.Skipped)
+		return &upload{}, nil
+	}
+	if info.Size() > u.opts.MaxSize {
+		return u.skipLarge(a, info.Size()), nil
+	}
+	data, err := readLimited(a.Source, u.opts.MaxSize+1)
+	if err != nil {
+		fmt.Printf("skipping attachment %s: %v\n", a.Source, err)
+		u.count(&u.stats.Skipped)
+		return &upload{}, nil
+	}
+	size := int64(len(data))
+	if size > u.opts.MaxSize {
+		return u.skipLarge(a, size), nil
+	}
+	contentType := detectType(a, data)
+	objectPath := objectName(data, a.Source, contentType)
+	object, first := u.once(u.objects, objectPath, func() (*upload, error) {
+		if err := u.storage.UploadObject(u.opts.Bucket, objectPath, contentType, data); err != nil {
+			return nil, err
+		}
+		u.count(&u.stats.Uploaded)
+		return &upload{path: objectPath}, nil
+	})
+	if object.err != nil {
+		return nil, object.err
+	}
+	if !first {
+		u.count(&u.stats.Deduplicated)
+	}
+	return &upload{path: objectPath, contentType: contentType, size: size}, nil
+}
+
+func (u *Uploader) skipLarge(a *models.SupaAttachment, size int64) *upload {
+	fmt.Printf("skipping attachment %s: size %d is over the limit %d\n", a.Source, size, u.opts.MaxSize)
+	u.count(&u.stats.Skipped)
+	return &upload{size: size}
+}
+
+// readLimited reads at most limit bytes of the file.
+func readLimited(p string, limit int64) ([]byte, error) {
+	f, err := files.Open(p)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return io.ReadAll(io.LimitReader(f, limit))
+}
+
+func (u *Uploader) count(counter *int) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	*counter++
+}
+
+// detectType returns the type reported by the framework, the type by the file extension
+// or the type detected by the content, in that order.
+func detectType(a *models.SupaAttachment, data []byte) string {
+	if a.Type != "" {
+		return a.Type
+	}
+	if t := mime.TypeByExtension(filepath.Ext(a.Source)); t != "" {
+		return t
+	}
+	return http.DetectContentType(data)
+}
+
+// objectName names the object by the sha256 of the content with the extension of the file,
+// so browsers can open it by the path.
+func objectName(data []byte, source, contentType string) string {
+	sum := sha256.Sum256(data)
+	name := hex.EncodeToString(sum[:])
+	ext := strings.ToLower(filepath.Ext(source))
+	if ext == "" {
+		if exts, err := mime.ExtensionsByType(contentType); err == nil && len(exts) > 0 {
+			ext = exts[0]
+		}
+	}
+	return name[:2] + "/" + name + ext
+}
@@ -0,0 +1,105 @@
+package attachments
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"test-inspector/internal/supabase"
+	"test-inspector/pkg/models"
+	"testing"
+)
+
+// storage is a stand-in of the storage API that counts uploads of objects and fails uploads of logs.
+type storage struct {
+	mu      sync.Mutex
+	objects map[string]int
+}
+
+func (s *storage) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	path := strings.TrimPrefix(r.URL.Path, "/storage/v1/object/attachments/")
+	switch {
+	case strings.HasSuffix(path, ".log"):
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(`{"statusCode":"500","error":"Internal","message":"broken"}`))
+	case s.objects[path] > 0:
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"statusCode":"409","error":"Duplicate","message":"The resource already exists"}`))
+	default:
+		w.WriteHeader(http.StatusOK)
+	}
+	s.objects[path]++
+}
+
+func writeFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	p := filepath.Join(dir, name)
+	if err := os.WriteFile(p, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func TestUploader(t *testing.T) {
+	dir := t.TempDir()
+	screenshot := writeFile(t, dir, "1-attachment.png", "same screenshot")
+	copied := writeFile(t, dir, "2-attachment.png", "same screenshot")
+	large := writeFile(t, dir, "3-attachment.txt", strings.Repeat("x", 100))
+	broken := writeFile(t, dir, "4-attachment.log", "log")
+
+	stand := &storage{objects: map[string]int{}}
+	server := httptest.NewServer(stand)
+	defer server.Close()
+	supa, err := supabase.CreateClient(server.URL, "key", supabase.UserCredentials{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	u := NewUploader(supa, Options{MaxSize: 50})
+
+	first := &models.SupaResult{Attachments: []*models.SupaAttachment{
+		{Name: "screenshot", Source: screenshot},
+		{Name: "large", Source: large},
+		{Name: "missing", Source: filepath.Join(dir, "missing.png")},
+	}}
+	second := &models.SupaResult{Attachments: []*models.SupaAttachment{
+		{Name: "screenshot", Source: screenshot},
+		{Name: "copy", Source: copied},
+		{Name: "log", Source: broken},
+	}}
+	if err = u.Upload(first); err != nil {
+		t.Fatalf("Upload() error = %v", err)
+	}
+	if err = u.Upload(second); err == nil || !strings.Contains(err.Error(), broken) {
+		t.Fatalf("Upload() error = %v, want the error of %s", err, broken)
+	}
+
+	sum := sha256.Sum256([]byte("same screenshot"))
+	name := hex.EncodeToString(sum[:])
+	wantPath := name[:2] + "/" + name + ".png"
+	for _, a := range []*models.SupaAttachment{first.Attachments[0], second.Attachments[0], second.Attachments[1]} {
+		if a.Path != wantPath || a.Type != "image/png" || a.Size != int64(len("same screenshot")) {
+			t.Errorf("attachment %s = %s %s %d, want %s", a.Name, a.Path, a.Type, a.Size, wantPath)
+		}
+	}
+	for _, a := range []*models.SupaAttachment{first.Attachments[1], first.Attachments[2], second.Attachments[2]} {
+		if a.Path != "" {
+			t.Errorf("attachment %s has path %s, want none", a.Name, a.Path)
+		}
+	}
+	if first.Attachments[1].Size != 100 {
+		t.Errorf("size of the large attachment is %d, want 100", first.Attachments[1].Size)
+	}
+	if stand.objects[wantPath] != 1 {
+		t.Errorf("screenshot uploaded %d times, want 1", stand.objects[wantPath])
+	}
+	want := Stats{Uploaded: 1, Deduplicated: 2, Skipped: 2, Failed: 1}
+	if got := u.Stats(); got != want {
+		t.Errorf("Stats() = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,239 @@
+// Package ctrf reads and writes reports in the common test report format (https://ctrf.io).
+package ctrf
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"test-inspector/pkg/diag"
+	"test-inspector/pkg/files"
+	"test-inspector/pkg/models"
+	"test-inspector/pkg/supatms"
+
+	"github.com/google/uuid"
+)
+
+// suiteSeparator is used by ctrf reporters to join the suite hierarchy into a single string.
+const suiteSeparator = " > "
+
+// Parser is a report parser for ctrf json reports.
+type Parser struct{}
+
+// Name returns the report type of ctrf json reports.
+func (Parser) Name() string {
+	return "ctrf"
+}
+
+// Detect checks if the path is a ctrf json file or a folder containing one.
+func (Parser) Detect(resultsPath string) bool {
+	reports, err := files.List(resultsPath, ".json")
+	if err != nil {
+		return false
+	}
+	for _, f := range reports {
+		if _, err := parseFile(f); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// ReadResults reads ctrf json report, see ReadResults.
+func (Parser) ReadResults(resultsPath string) (map[uuid.UUID]models.SupaResult, error) {
+	return ReadResults(resultsPath)
+}
+
+// ReadResults reads ctrf json report file or all such files in the folder
+// and returns them as a map of SupaResults
+func ReadResults(resultsPath string) (map[uuid.UUID]models.SupaResult, error) {
+	reports, err := files.List(resultsPath, ".json")
+	if err != nil {
+		return nil, err
+	}
+
+	results := map[uuid.UUID]models.SupaResult{}
+	diagnostics := &diag.Collector{}
+	for _, f := range reports {
+		report, err := parseFile(f)
+		if err != nil {
+			// other json files are skipped silently, only broken ones are reported
+			if diag.Malformed(err) {
+				diagnostics.Add(diag.Skip(f, err))
+			}
+			continue
+		}
+		for _, test := range report.Results.Tests {
+			ar, err := convertTest(report.Results.Tool, test)
+			if err != nil {
+				diagnostics.Add(diag.Skip(f, fmt.Errorf("test %s: %v", test.Name, err)))
+				continue
+			}
+			steps, err := supatms.StepsJSON(ar)
+			if err != nil {
+				diagnostics.Add(diag.Warn(f, diag.Unsupported,
+					fmt.Sprintf("steps of %s could not be read: %v", ar.Name, err)))
+			}
+			res := supatms.ToResult(0, *ar, steps)
+			if test.Retries > 0 {
+				res.Attempts = int16(test.Retries + 1)
+			}
+			results[res.ID] = res
+		}
+	}
+	return results, diagnostics.Err()
+}
+
+// parseFile reads the file and returns an error if it is not a ctrf report.
+func parseFile(path string) (*models.CtrfReport, error) {
+	jsonFile, err := files.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var report models.CtrfReport
+	if err = json.Unmarshal(jsonFile, &report); err != nil {
+		return nil, err
+	}
+	if report.Results == nil || report.Results.Tool.Name == "" || report.Results.Tests == nil {
+		return nil, fmt.Errorf("%s is not a ctrf report", path)
+	}
+	return &report, nil
+}
+
+func convertTest(tool models.CtrfTool, test *models.CtrfTest) (*models.AllureResult, error) {
+	suites, err := parseSuite(test.Suite)
+	if err != nil {
+		return nil, err
+	}
+	if len(suites) == 0 && test.FilePath != "" {
+		suites = []string{strings.TrimSuffix(filepath.Base(test.FilePath), filepath.Ext(test.FilePath))}
+	}
+	fullName := strings.Join(append(suites, test.Name), suiteSeparator)
+
+	ar := &models.AllureResult{
+		Name:     test.Name,
+		Status:   convertStatus(test.Status, test.RawStatus),
+		Steps:    convertSteps(test.Steps),
+		Start:    test.Start,
+		Stop:     test.Start + test.Duration,
+		UUID:     uuid.New(),
+		FullName: &fullName,
+		Labels:   supatms.SuiteLabels(suites),
+	}
+	// reports exported by test-inspector keep the framework of the test in the extra
+	if _, ok := test.Extra["framework"]; !ok {
+		ar.Labels = append(ar.Labels, &models.Label{Name: "framework", Value: tool.Name})
+	}
+	if test.FilePath != "" {
+		ar.Labels = append(ar.Labels, &models.Label{Name: "package", Value: test.FilePath})
+	}
+	for _, tag := range test.Tags {
+		ar.Labels = append(ar.Labels, &models.Label{Name: "tag", Value: tag})
+	}
+	ar.Labels = append(ar.Labels, extraLabels(test.Extra)...)
+
+	if test.Message != "" || test.Trace != "" || test.Flaky {
+		ar.StatusDetails = &models.StatusDetails{
+			Flaky:   test.Flaky,
+			Message: nonEmptyRef(test.Message),
+			Trace:   nonEmptyRef(test.Trace),
+		}
+	}
+	return ar, nil
+}
+
+// parseSuite reads the suite of the test. Older reporters write a single string
+// with suites joined by ` > `, newer ones write an array of suites.
+func parseSuite(raw json.RawMessage) ([]string, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil, nil
+	}
+	var suites []string
+	if err := json.Unmarshal(raw, &suites); err == nil {
+		return suites, nil
+	}
+	var suite string
+	if err := json.Unmarshal(raw, &suite); err != nil {
+		return nil, fmt.Errorf("suite should be a string or an array of strings: %v", err)
+	}
+	if suite == "" {
+		return nil, nil
+	}
+	return strings.Split(suite, suiteSeparator), nil
+}
+
+// extraLabels converts extra properties of the test to labels, so labels like `feature`
+// or `epic` written by the reporter are picked up. Non string values are kept as json.
+func extraLabels(extra map[string]interface{}) []*models.Label {
+	labels := []*models.Label{}
+	for _, name := range sortedKeys(extra) {
+		switch v := extra[name].(type) {
+		case nil:
+			continue
+		case string:
+			labels = append(labels, &models.Label{Name: name, Value: v})
+		default:
+			raw, err := json.Marshal(v)
+			if err != nil {
+				continue
+			}
+			labels = append(labels, &models.Label{Name: name, Value: string(raw)})
+		}
+	}
+	return labels
+}
+
+func convertSteps(steps []*models.CtrfStep) []*models.Step {
+	res := []*models.Step{}
+	for _, s := range steps {
+		status := convertStatus(s.Status, "")
+		res = append(res, &models.Step{
+			Name:   s.Name,
+			Status: &status,
+			Steps:  convertSteps(nestedSteps(s)),
+		})
+	}
+	return res
+}
+
+// nestedSteps returns the nested steps kept in the extra properties of the step,
+// ctrf steps are flat, so the exporter puts nested steps there.
+func nestedSteps(s *models.CtrfStep) []*models.CtrfStep {
+	raw, ok := s.Extra["steps"]
+	if !ok {
+		return nil
+	}
+	stepsRaw, err := json.Marshal(raw)
+	if err != nil {
+		return nil
+	}
+	var steps []*models.CtrfStep
+	if err := json.Unmarshal(stepsRaw, &steps); err != nil {
+		return nil
+	}
+	return steps
+}
+
+// convertStatus maps ctrf status to the allure one.
+// The raw status is used for `other` if it is a known allure status, for ex. `broken`,
+// and for `failed` if it is `broken`, as exported reports keep broken tests this way.
+func convertStatus(status, rawStatus string) string {
+	switch {
+	case status == "failed" && rawStatus == "broken":
+		return rawStatus
+	case status == "passed", status == "failed", status == "skipped", status == "pending":
+		return status
+	}
+	switch rawStatus {
+	case "broken", "unknown":
+		return rawStatus
+	}
+	return "unknown"
+}
+
+func nonEmptyRef(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
@@ -0,0 +1,140 @@
+package ctrf
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"test-inspector/internal/parsertest"
+	"test-inspector/pkg/diag"
+	"test-inspector/pkg/models"
+	"testing"
+)
+
+func TestReadResults(t *testing.T) {
+	tests := []struct {
+		name      string
+		want      parsertest.Want
+		flaky     bool
+		start     int64
+		stop      int64
+		framework string
+	}{
+		{
+			name: "works with email",
+			want: parsertest.Want{
+				FullName: "Auth > sign in > with sso > works with email", ParentSuite: "Auth", Suite: "sign in", SubSuite: "with sso",
+				Status: "failed", Message: "expected 200, got 401",
+				Steps:    []string{"open the page: passed", "sign in: failed", "  fill the form: failed"},
+				Attempts: 3,
+			},
+			start: 1714557600000, stop: 1714557601200,
+		},
+		{
+			name:  "rejects anonymous users",
+			want:  parsertest.Want{FullName: "Auth > rejects anonymous users", Suite: "Auth", Status: "passed", Attempts: 2},
+			flaky: true, start: 1714557600000, stop: 1714557600020,
+		},
+		{
+			// tests without suites are grouped by the file
+			name: "responds",
+			want: parsertest.Want{FullName: "health.test > responds", Suite: "health.test", Status: "skipped"},
+		},
+		{
+			// the raw status is kept if it is a known allure status
+			name:  "uploads",
+			want:  parsertest.Want{FullName: "Storage > uploads", Suite: "Storage", Status: "broken", Message: "connection reset"},
+			start: 1714557601200, stop: 1714557601500,
+		},
+	}
+	results, d := parsertest.Read(t, ReadResults, "testdata")
+	parsertest.Malformed(t, d, "broken.json", diag.Syntax)
+	if len(results) != len(tests) {
+		t.Fatalf("results %v, want %d", results.Names(), len(tests))
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := results.Find(t, tt.name)
+			parsertest.Check(t, r, tt.want)
+			if flaky := r.StatusDetails != nil && r.StatusDetails.Flaky; flaky != tt.flaky {
+				t.Errorf("flaky %v, want %v", flaky, tt.flaky)
+			}
+			if r.Start != tt.start || r.Stop != tt.stop {
+				t.Errorf("start %d, stop %d, want %d, %d", r.Start, r.Stop, tt.start, tt.stop)
+			}
+			if fw := parsertest.Label(r, "framework"); !reflect.DeepEqual(fw, []string{"jest"}) {
+				t.Errorf("framework %v, want the tool", fw)
+			}
+		})
+	}
+
+	// extra properties are labels, non string values are kept as json
+	r := results.Find(t, "works with email")
+	labels := map[string][]string{
+		"feature":        {"Sign in"},
+		"owner":          {"auth-team"},
+		"retriesAllowed": {"3"},
+		"tag":            {"smoke"},
+		"package":        {"tests/auth.test.js"},
+	}
+	for name, want := range labels {
+		if got := parsertest.Label(r, name); !reflect.DeepEqual(got, want) {
+			t.Errorf("label %s %v, want %v", name, got, want)
+		}
+	}
+}
+
+func TestReadResultsInvalidSuite(t *testing.T) {
+	dir := t.TempDir()
+	report := `{"results": {"tool": {"name": "jest"}, "tests": [
+		{"name": "valid", "status": "passed", "duration": 1, "suite": "Auth"},
+		{"name": "invalid", "status": "passed", "duration": 1, "suite": 42}
+	]}}`
+	if err := os.WriteFile(filepath.Join(dir, "ctrf.json"), []byte(report), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	results, d := parsertest.Read(t, ReadResults, dir)
+	parsertest.Malformed(t, d, "ctrf.json", diag.Unsupported)
+	if !reflect.DeepEqual(results.Names(), []string{"valid"}) {
+		t.Errorf("results %v, want only the test with a valid suite", results.Names())
+	}
+}
+
+func TestWriteReadResults(t *testing.T) {
+	results, _ := parsertest.Read(t, ReadResults, "testdata")
+	dir := t.TempDir()
+	f, err := os.Create(filepath.Join(dir, "ctrf.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	report := NewReport(Tool, []models.SupaResult(results))
+	if err = Write(f, report); err != nil {
+		t.Fatal(err)
+	}
+	if err = f.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if s := report.Results.Summary; s.Tests != 4 || s.Passed != 1 || s.Failed != 2 || s.Skipped != 1 {
+		t.Errorf("summary %+v, want broken tests counted as failed", s)
+	}
+
+	read, d := parsertest.Read(t, ReadResults, dir)
+	if len(d) != 0 {
+		t.Errorf("diagnostics %v, want none", d)
+	}
+	if !reflect.DeepEqual(read.Names(), results.Names()) {
+		t.Fatalf("results %v, want %v", read.Names(), results.Names())
+	}
+	for _, r := range results {
+		got := read.Find(t, r.Name)
+		want := parsertest.Want{
+			FullName: r.FullName, ParentSuite: r.ParentSuite, Suite: r.Suite, SubSuite: r.SubSuite,
+			Status: r.Status, Steps: parsertest.Steps(t, r), Attempts: r.Attempts,
+		}
+		parsertest.Check(t, got, want)
+		// the framework of the test is kept in the extra and not replaced by the tool
+		if fw := parsertest.Label(got, "framework"); !reflect.DeepEqual(fw, []string{"jest"}) {
+			t.Errorf("%s: framework %v, want jest", r.Name, fw)
+		}
+	}
+}
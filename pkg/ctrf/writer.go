@@ -0,0 +1,200 @@
+package ctrf
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"sort"
+	"strings"
+	"test-inspector/pkg/models"
+	"time"
+)
+
+// Tool is the tool name of exported reports, frameworks of the tests are kept in the extra.
+const Tool = "test-inspector"
+
+// skippedLabels are labels already mapped to ctrf test fields, so they are not repeated in extra.
+var skippedLabels = map[string]bool{
+	"parentSuite": true,
+	"suite":       true,
+	"subSuite":    true,
+	"package":     true,
+	"tag":         true,
+}
+
+// NewReport converts results to the ctrf report of the tool.
+// Results are sorted by suites and names to keep the report stable.
+func NewReport(tool string, results []models.SupaResult) *models.CtrfReport {
+	sorted := append([]models.SupaResult{}, results...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sortKey(sorted[i]) < sortKey(sorted[j])
+	})
+
+	report := &models.CtrfReport{
+		Results: &models.CtrfResults{
+			Tool:  models.CtrfTool{Name: tool},
+			Tests: []*models.CtrfTest{},
+		},
+	}
+	summary := &report.Results.Summary
+	for _, r := range sorted {
+		test := convertResult(r)
+		report.Results.Tests = append(report.Results.Tests, test)
+		addToSummary(summary, test.Status)
+		if test.Start > 0 && (summary.Start == 0 || test.Start < summary.Start) {
+			summary.Start = test.Start
+		}
+		if test.Stop > summary.Stop {
+			summary.Stop = test.Stop
+		}
+	}
+	// results without timestamps are considered to be finished now
+	if summary.Stop == 0 {
+		summary.Stop = time.Now().UnixMilli()
+	}
+	if summary.Start == 0 {
+		summary.Start = summary.Stop
+	}
+	return report
+}
+
+// Write writes the report as indented json.
+func Write(w io.Writer, report *models.CtrfReport) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	// suites are joined with ` > `, keep it readable
+	encoder.SetEscapeHTML(false)
+	return encoder.Encode(report)
+}
+
+// rawString encodes the string as json without escaping `>` of joined suites.
+func rawString(s string) json.RawMessage {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	_ = encoder.Encode(s)
+	return bytes.TrimSpace(buf.Bytes())
+}
+
+func sortKey(r models.SupaResult) string {
+	return strings.Join([]string{r.ParentSuite, r.Suite, r.SubSuite, r.Name, r.FullName}, "\x00")
+}
+
+func convertResult(r models.SupaResult) *models.CtrfTest {
+	test := &models.CtrfTest{
+		Name:      r.Name,
+		Status:    exportStatus(r.Status),
+		RawStatus: r.Status,
+		Duration:  int64(r.Duration),
+		Start:     r.Start,
+		Stop:      r.Stop,
+		Steps:     exportSteps(r),
+		Extra:     map[string]interface{}{},
+	}
+	suites := []string{}
+	for _, s := range []string{r.ParentSuite, r.Suite, r.SubSuite} {
+		if s != "" {
+			suites = append(suites, s)
+		}
+	}
+	if len(suites) > 0 {
+		test.Suite = rawString(strings.Join(suites, suiteSeparator))
+	}
+	if r.Attempts > 1 {
+		test.Retries = int(r.Attempts) - 1
+	}
+	if r.StatusDetails != nil {
+		test.Flaky = r.StatusDetails.Flaky
+		if r.StatusDetails.Message != nil {
+			test.Message = *r.StatusDetails.Message
+		}
+		if r.StatusDetails.Trace != nil {
+			test.Trace = *r.StatusDetails.Trace
+		}
+	}
+
+	if r.Feature != "" {
+		test.Extra["feature"] = r.Feature
+	}
+	if r.Description != nil && *r.Description != "" {
+		test.Extra["description"] = *r.Description
+	}
+	for _, l := range r.Labels {
+		switch {
+		case l.Name == "tag":
+			test.Tags = append(test.Tags, l.Value)
+		case l.Name == "package":
+			test.FilePath = l.Value
+		case !skippedLabels[l.Name]:
+			test.Extra[l.Name] = l.Value
+		}
+	}
+	if len(test.Extra) == 0 {
+		test.Extra = nil
+	}
+	return test
+}
+
+// exportStatus maps allure status to the ctrf one, the original status is kept as raw status.
+func exportStatus(status string) string {
+	switch status {
+	case "passed", "failed", "skipped", "pending":
+		return status
+	case "broken":
+		return "failed"
+	default:
+		return "other"
+	}
+}
+
+func exportSteps(r models.SupaResult) []*models.CtrfStep {
+	if r.Steps == "" {
+		return nil
+	}
+	var steps []*models.StepContainer
+	if err := json.Unmarshal([]byte(r.Steps), &steps); err != nil {
+		return nil
+	}
+	return convertStepContainers(steps)
+}
+
+// convertStepContainers converts the step tree, nested steps are kept in the extra of the parent step.
+func convertStepContainers(steps []*models.StepContainer) []*models.CtrfStep {
+	res := []*models.CtrfStep{}
+	for _, s := range steps {
+		step := &models.CtrfStep{
+			Name:   s.Name,
+			Status: exportStatus(s.Status),
+		}
+		if len(s.StepContainer) > 0 {
+			step.Extra = map[string]interface{}{"steps": convertStepContainers(s.StepContainer)}
+		}
+		res = append(res, step)
+	}
+	return res
+}
+
+func addToSummary(s *models.CtrfSummary, status string) {
+	s.Tests++
+	switch status {
+	case "passed":
+		s.Passed++
+	case "failed":
+		s.Failed++
+	case "pending":
+		s.Pending++
+	case "skipped":
+		s.Skipped++
+	default:
+		s.Other++
+	}
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,308 @@
+package cucumber
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"test-inspector/pkg/diag"
+	"test-inspector/pkg/files"
+	"test-inspector/pkg/models"
+	"test-inspector/pkg/supatms"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Parser is a report parser for cucumber json reports.
+type Parser struct{}
+
+// Name returns the report type of cucumber json reports.
+func (Parser) Name() string {
+	return "cucumber"
+}
+
+// Detect checks if the path is a cucumber json file or a folder containing one.
+func (Parser) Detect(resultsPath string) bool {
+	reports, err := files.List(resultsPath, ".json")
+	if err != nil {
+		return false
+	}
+	for _, f := range reports {
+		if features, err := parseFile(f); err == nil && isCucumberReport(features) {
+			return true
+		}
+	}
+	return false
+}
+
+// ReadResults reads cucumber json report, see ReadResults.
+func (Parser) ReadResults(resultsPath string) (map[uuid.UUID]models.SupaResult, error) {
+	return ReadResults(resultsPath)
+}
+
+// ReadResults reads cucumber json report file or all such files in the folder
+// and returns scenarios as a map of SupaResults
+func ReadResults(resultsPath string) (map[uuid.UUID]models.SupaResult, error) {
+	reports, err := files.List(resultsPath, ".json")
+	if err != nil {
+		return nil, err
+	}
+
+	results := map[uuid.UUID]models.SupaResult{}
+	diagnostics := &diag.Collector{}
+	for _, f := range reports {
+		features, err := parseFile(f)
+		if err != nil {
+			// other json files are skipped silently, only broken ones are reported
+			if diag.Malformed(err) {
+				diagnostics.Add(diag.Skip(f, err))
+			}
+			continue
+		}
+		if !isCucumberReport(features) {
+			continue
+		}
+		for _, feature := range features {
+			for _, ar := range convertFeature(feature) {
+				res, err := toResult(ar)
+				if err != nil {
+					diagnostics.Add(diag.Skip(f, err))
+					continue
+				}
+				results[res.ID] = res
+			}
+		}
+	}
+	return results, diagnostics.Err()
+}
+
+func toResult(sr *scenarioResult) (models.SupaResult, error) {
+	steps, err := supatms.StepsJSON(sr.result)
+	if err != nil {
+		return models.SupaResult{}, fmt.Errorf("problems with parsing steps: %s name - %s. %v",
+			sr.result.UUID, sr.result.Name, err)
+	}
+	res := supatms.ToResult(0, *sr.result, steps)
+	if res.Befores, err = supatms.StepsJSON(sr.befores); err != nil {
+		return models.SupaResult{}, fmt.Errorf("problems with parsing hooks: %s name - %s. %v",
+			sr.result.UUID, sr.result.Name, err)
+	}
+	if res.Afters, err = supatms.StepsJSON(sr.afters); err != nil {
+		return models.SupaResult{}, fmt.Errorf("problems with parsing hooks: %s name - %s. %v",
+			sr.result.UUID, sr.result.Name, err)
+	}
+	return res, nil
+}
+
+func parseFile(path string) ([]*models.CucumberFeature, error) {
+	jsonFile, err := files.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var features []*models.CucumberFeature
+	if err = json.Unmarshal(jsonFile, &features); err != nil {
+		return nil, err
+	}
+	return features, nil
+}
+
+func isCucumberReport(features []*models.CucumberFeature) bool {
+	if len(features) == 0 {
+		return false
+	}
+	for _, f := range features {
+		if f.Keyword == "" || f.Elements == nil {
+			return false
+		}
+	}
+	return true
+}
+
+// scenarioResult is a scenario converted to allure result with its hooks.
+type scenarioResult struct {
+	result  *models.AllureResult
+	befores *models.Step
+	afters  *models.Step
+}
+
+func convertFeature(f *models.CucumberFeature) []*scenarioResult {
+	res := []*scenarioResult{}
+	var background *models.CucumberElement
+	for _, e := range f.Elements {
+		if e.Type == "background" {
+			background = e
+			continue
+		}
+		res = append(res, convertScenario(f, background, e))
+		background = nil
+	}
+	return res
+}
+
+func convertScenario(f *models.CucumberFeature, background, scenario *models.CucumberElement) *scenarioResult {
+	steps := scenario.Steps
+	if background != nil {
+		steps = append(append([]*models.CucumberStep{}, background.Steps...), scenario.Steps...)
+	}
+
+	ar := &models.AllureResult{
+		Name:        scenario.Name,
+		Steps:       convertSteps(steps),
+		UUID:        uuid.New(),
+		FullName:    stringRef(scenario.ID),
+		Description: nonEmptyRef(scenario.Description),
+		Labels: []*models.Label{
+			{
+				Name:  "feature",
+				Value: f.Name,
+			},
+			{
+				Name:  "suite",
+				Value: f.Name,
+			},
+			{
+				Name:  "framework",
+				Value: "cucumber",
+			},
+		},
+		Parameters: outlineParameters(scenario),
+	}
+	for _, t := range append(append([]*models.CucumberTag{}, f.Tags...), scenario.Tags...) {
+		ar.Labels = append(ar.Labels, &models.Label{Name: "tag", Value: strings.TrimPrefix(t.Name, "@")})
+	}
+
+	befores := &models.Step{Steps: convertHooks(scenario.Before, "Before")}
+	afters := &models.Step{Steps: convertHooks(scenario.After, "After")}
+	all := concatSteps(befores.Steps, ar.Steps, afters.Steps)
+	ar.Status = scenarioStatus(all)
+	ar.StatusDetails = errorDetails(all)
+
+	var duration int64
+	for _, s := range all {
+		duration += s.Stop - s.Start
+	}
+	if scenario.StartTimestamp != "" {
+		if start, err := time.Parse(time.RFC3339Nano, scenario.StartTimestamp); err == nil {
+			ar.Start = start.UnixMilli()
+		}
+	}
+	ar.Stop = ar.Start + duration
+
+	return &scenarioResult{result: ar, befores: befores, afters: afters}
+}
+
+// outlineParameters returns the example of the scenario outline the scenario was expanded from.
+// Cucumber expands outlines itself and adds the examples table and the row to the scenario id.
+// The line of the scenario is not a parameter, it changes with every edit above the outline.
+func outlineParameters(scenario *models.CucumberElement) []*models.Parameter {
+	if !strings.Contains(strings.ToLower(scenario.Keyword), "outline") &&
+		!strings.Contains(strings.ToLower(scenario.Keyword), "template") {
+		return nil
+	}
+	parts := strings.Split(scenario.ID, ";")
+	if len(parts) < 2 {
+		return nil
+	}
+	params := []*models.Parameter{}
+	if row := parts[len(parts)-1]; row != "" {
+		if _, err := strconv.Atoi(row); err == nil {
+			params = append(params, &models.Parameter{Name: "example", Value: row})
+		}
+	}
+	if len(parts) >= 4 && parts[len(parts)-2] != "" {
+		params = append(params, &models.Parameter{Name: "examples", Value: parts[len(parts)-2]})
+	}
+	return params
+}
+
+func convertSteps(steps []*models.CucumberStep) []*models.Step {
+	res := []*models.Step{}
+	for _, s := range steps {
+		res = append(res, convertStep(strings.TrimSpace(s.Keyword)+" "+s.Name, s.Result))
+	}
+	return res
+}
+
+func convertHooks(hooks []*models.CucumberHook, name string) []*models.Step {
+	res := []*models.Step{}
+	for _, h := range hooks {
+		hookName := name
+		if h.Match.Location != "" {
+			hookName += " " + h.Match.Location
+		}
+		res = append(res, convertStep(hookName, h.Result))
+	}
+	return res
+}
+
+func convertStep(name string, r models.CucumberResult) *models.Step {
+	status := convertStatus(r.Status)
+	s := &models.Step{
+		Name:   name,
+		Status: &status,
+		Stop:   time.Duration(r.Duration).Milliseconds(),
+	}
+	if r.ErrorMessage != "" {
+		message := strings.SplitN(r.ErrorMessage, "\n", 2)[0]
+		trace := r.ErrorMessage
+		s.StatusDetails = &models.StatusDetails{
+			Message: &message,
+			Trace:   &trace,
+		}
+	}
+	return s
+}
+
+func convertStatus(status string) string {
+	switch status {
+	case "passed", "failed", "skipped":
+		return status
+	case "pending", "undefined":
+		return "skipped"
+	case "ambiguous":
+		return "broken"
+	default:
+		return "unknown"
+	}
+}
+
+// scenarioStatus returns the worst status of scenario steps and hooks.
+func scenarioStatus(steps []*models.Step) string {
+	priority := map[string]int{"passed": 0, "skipped": 1, "unknown": 2, "broken": 3, "failed": 4}
+	status := "passed"
+	for _, s := range steps {
+		if priority[*s.Status] > priority[status] {
+			status = *s.Status
+		}
+	}
+	return status
+}
+
+func concatSteps(stepGroups ...[]*models.Step) []*models.Step {
+	res := []*models.Step{}
+	for _, steps := range stepGroups {
+		res = append(res, steps...)
+	}
+	return res
+}
+
+func errorDetails(steps []*models.Step) *models.StatusDetails {
+	for _, s := range steps {
+		if s.StatusDetails != nil {
+			return s.StatusDetails
+		}
+	}
+	return nil
+}
+
+func stringRef(s string) *string {
+	return &s
+}
+
+func nonEmptyRef(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
@@ -0,0 +1,77 @@
+package cucumber
+
+import (
+	"reflect"
+	"strings"
+	"test-inspector/internal/parsertest"
+	"test-inspector/pkg/diag"
+	"testing"
+	"time"
+)
+
+func TestReadResults(t *testing.T) {
+	results, d := parsertest.Read(t, ReadResults, "testdata")
+	parsertest.Malformed(t, d, "broken.json", diag.Syntax)
+	if len(results) != 3 {
+		t.Fatalf("results %v, want 3 scenarios", results.Names())
+	}
+
+	r := results.Find(t, "Sign in with email")
+	parsertest.Check(t, r, parsertest.Want{
+		FullName: "sign-in;sign-in-with-email", Suite: "Sign in", Status: "failed", Message: "expected dashboard",
+		Steps: []string{
+			"Given the app is open: passed",
+			`When I sign in as "ann": passed`,
+			"Then I see the dashboard: failed",
+		},
+	})
+	if r.Feature != "Sign in" {
+		t.Errorf("feature %q, want %q", r.Feature, "Sign in")
+	}
+	if tags := parsertest.Label(r, "tag"); !reflect.DeepEqual(tags, []string{"auth", "smoke"}) {
+		t.Errorf("tags %v, want the tag of the feature and the scenario", tags)
+	}
+	if !strings.Contains(r.Befores, "Before hooks.js:3") || !strings.Contains(r.Afters, "After hooks.js:9") {
+		t.Errorf("hooks %s %s, want before and after hooks", r.Befores, r.Afters)
+	}
+	start := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC).UnixMilli()
+	if r.Start != start || r.Stop != start+10 {
+		t.Errorf("start %d, stop %d, want %d and the sum of steps and hooks", r.Start, r.Stop, start)
+	}
+}
+
+func TestReadResultsOutline(t *testing.T) {
+	tests := []struct {
+		step   string
+		status string
+		params []string
+	}{
+		{`When I sign in as "admin": passed`, "passed", []string{"example=2", "examples=roles"}},
+		{`When I sign in as "guest": skipped`, "skipped", []string{"example=3", "examples=roles"}},
+	}
+	results, _ := parsertest.Read(t, ReadResults, "testdata")
+	rows := results.All("Sign in as role")
+	if len(rows) != len(tests) {
+		t.Fatalf("%d rows of the outline, want %d", len(rows), len(tests))
+	}
+	for _, tt := range tests {
+		t.Run(tt.params[0], func(t *testing.T) {
+			for _, r := range rows {
+				params := []string{}
+				for _, p := range r.Parameters {
+					params = append(params, p.Name+"="+p.Value)
+				}
+				if !reflect.DeepEqual(params, tt.params) {
+					continue
+				}
+				// the line of the scenario is not a parameter, so rows match after edits of the feature file
+				parsertest.Check(t, r, parsertest.Want{
+					Suite: "Sign in", Status: tt.status,
+					Steps: []string{"Given the app is open: passed", tt.step},
+				})
+				return
+			}
+			t.Errorf("no row with parameters %v", tt.params)
+		})
+	}
+}
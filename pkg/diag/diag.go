@@ -0,0 +1,220 @@
+// Package diag describes problems with input files found by parsers. Parsers skip malformed files
+// and return diagnostics as an error together with results of well-formed files, so the caller decides
+// if the problems are fatal, see From.
+package diag
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"sort"
+	"strings"
+	"sync"
+	"test-inspector/pkg/files"
+)
+
+// Severity is how bad the problem is.
+type Severity string
+
+const (
+	// Warning is a problem that does not lose tests, for ex. steps that could not be read.
+	Warning Severity = "warning"
+	// Error is a malformed file that was skipped, its tests are missing from results.
+	Error Severity = "error"
+)
+
+// Kinds of problems used to summarize diagnostics.
+const (
+	Syntax      = "syntax error"
+	Type        = "unexpected value type"
+	Truncated   = "truncated file"
+	Unreadable  = "unreadable file"
+	Unsupported = "unsupported content"
+	Incomplete  = "incomplete result"
+)
+
+// Diagnostic is a problem with the input file.
+// @property {string} File - The path of the file, files in archives are `archive.zip/path/in/archive`.
+// @property {int} Line - The line of the problem starting from 1, 0 if it is unknown.
+// @property {int} Column - The column of the problem starting from 1, 0 if it is unknown.
+// @property {int64} Offset - The byte offset of the problem, 0 if it is unknown.
+// @property {string} Kind - The kind of the problem, see Syntax, Type etc.
+// @property {string} Reason - The description of the problem.
+// @property {Severity} Severity - The severity of the problem.
+type Diagnostic struct {
+	File     string   `json:"file"`
+	Line     int      `json:"line,omitempty"`
+	Column   int      `json:"column,omitempty"`
+	Offset   int64    `json:"offset,omitempty"`
+	Kind     string   `json:"kind"`
+	Reason   string   `json:"reason"`
+	Severity Severity `json:"severity"`
+}
+
+// String formats the diagnostic as `severity: file:line:column: reason`.
+func (d Diagnostic) String() string {
+	location := d.File
+	if d.Line > 0 {
+		location += fmt.Sprintf(":%d", d.Line)
+		if d.Column > 0 {
+			location += fmt.Sprintf(":%d", d.Column)
+		}
+	}
+	return fmt.Sprintf("%s: %s: %s", d.Severity, location, d.Reason)
+}
+
+// Skip returns the error diagnostic of the malformed file that was skipped.
+// The position is found by the offset of json errors and the line of xml errors.
+func Skip(file string, err error) Diagnostic {
+	d := Diagnostic{File: file, Kind: Unsupported, Reason: err.Error(), Severity: Error}
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	var xmlErr *xml.SyntaxError
+	var pathErr *fs.PathError
+	switch {
+	case errors.As(err, &syntaxErr):
+		d.Kind, d.Offset = Syntax, syntaxErr.Offset
+	case errors.As(err, &typeErr):
+		d.Kind, d.Offset = Type, typeErr.Offset
+	case errors.As(err, &xmlErr):
+		d.Kind, d.Line = Syntax, xmlErr.Line
+		if strings.Contains(xmlErr.Msg, "unexpected EOF") {
+			d.Kind = Truncated
+		}
+	case errors.Is(err, io.ErrUnexpectedEOF):
+		d.Kind = Truncated
+	case errors.As(err, &pathErr):
+		d.Kind = Unreadable
+	}
+	if d.Offset > 0 {
+		d.Line, d.Column = position(file, d.Offset)
+	}
+	return d
+}
+
+// Malformed checks if the error means the file is broken, not just a file of another format,
+// so parsers of folders with mixed files can skip other files silently.
+func Malformed(err error) bool {
+	var syntaxErr *json.SyntaxError
+	var xmlErr *xml.SyntaxError
+	var pathErr *fs.PathError
+	return errors.As(err, &syntaxErr) || errors.As(err, &xmlErr) || errors.As(err, &pathErr) ||
+		errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// Warn returns the warning diagnostic of the file.
+func Warn(file, kind, reason string) Diagnostic {
+	return Diagnostic{File: file, Kind: kind, Reason: reason, Severity: Warning}
+}
+
+// position returns the line and column of the byte offset in the file.
+func position(file string, offset int64) (int, int) {
+	f, err := files.Open(file)
+	if err != nil {
+		return 0, 0
+	}
+	defer f.Close()
+	data, err := io.ReadAll(io.LimitReader(f, offset))
+	if err != nil {
+		return 0, 0
+	}
+	// the offset is after the last byte read, which is the one with the problem
+	line := 1 + strings.Count(string(data), "\n")
+	column := len(data) - strings.LastIndex(string(data), "\n") - 1
+	return line, column
+}
+
+// Diagnostics are problems found while reading the report.
+// They are returned as an error together with results of well-formed files.
+type Diagnostics []Diagnostic
+
+// Error summarizes diagnostics, see Summary.
+func (d Diagnostics) Error() string {
+	return d.Summary()
+}
+
+// Errors returns the number of error diagnostics, those are skipped files.
+func (d Diagnostics) Errors() int {
+	n := 0
+	for _, x := range d {
+		if x.Severity == Error {
+			n++
+		}
+	}
+	return n
+}
+
+// Summary tells how many files were skipped and why, for ex. `2 files skipped: 1 syntax error, 1 truncated file`.
+// Warnings are counted separately.
+func (d Diagnostics) Summary() string {
+	kinds := map[string]int{}
+	for _, x := range d {
+		if x.Severity == Error {
+			kinds[x.Kind]++
+		}
+	}
+	names := make([]string, 0, len(kinds))
+	for k := range kinds {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	reasons := make([]string, 0, len(names))
+	for _, k := range names {
+		reasons = append(reasons, fmt.Sprintf("%d %s", kinds[k], k))
+	}
+
+	summary := fmt.Sprintf("%d files skipped", d.Errors())
+	if len(reasons) > 0 {
+		summary += ": " + strings.Join(reasons, ", ")
+	}
+	if warnings := len(d) - d.Errors(); warnings > 0 {
+		summary += fmt.Sprintf(", %d warnings", warnings)
+	}
+	return summary
+}
+
+// From splits the error returned by the parser to diagnostics and the fatal error.
+// The fatal error is nil if the parser only skipped malformed files.
+func From(err error) (Diagnostics, error) {
+	if err == nil {
+		return nil, nil
+	}
+	var d Diagnostics
+	if errors.As(err, &d) {
+		return d, nil
+	}
+	return nil, err
+}
+
+// Collector collects diagnostics of a single read, it is safe for concurrent use.
+type Collector struct {
+	mu   sync.Mutex
+	list Diagnostics
+}
+
+// Add adds diagnostics to the collector.
+func (c *Collector) Add(d ...Diagnostic) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.list = append(c.list, d...)
+}
+
+// Err returns collected diagnostics sorted by files, or nil if there are none.
+func (c *Collector) Err() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.list) == 0 {
+		return nil
+	}
+	list := append(Diagnostics{}, c.list...)
+	sort.SliceStable(list, func(i, j int) bool {
+		if list[i].File != list[j].File {
+			return list[i].File < list[j].File
+		}
+		return list[i].Offset < list[j].Offset
+	})
+	return list
+}
@@ -0,0 +1,118 @@
+package diag
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSkip(t *testing.T) {
+	decode := func(file string, v interface{}) error {
+		data, err := os.ReadFile(filepath.Join("testdata", file))
+		if err != nil {
+			return err
+		}
+		return json.Unmarshal(data, v)
+	}
+	var result struct {
+		Name  string `json:"name"`
+		Start int64  `json:"start"`
+	}
+	var report struct {
+		XMLName xml.Name `xml:"testsuite"`
+	}
+	tests := []struct {
+		name, file   string
+		err          error
+		kind         string
+		line, column int
+	}{
+		{"json syntax", "syntax.json", decode("syntax.json", &result), Syntax, 3, 13},
+		{"json type", "type.json", decode("type.json", &result), Type, 3, 22},
+		{"json truncated", "truncated.json", decode("truncated.json", &result), Syntax, 2, 14},
+		{"xml syntax", "report.xml", xml.Unmarshal([]byte("<testsuite>\n<testcase name=x/>\n</testsuite>"), &report), Syntax, 2, 0},
+		{"xml truncated", "report.xml", xml.Unmarshal([]byte("<testsuite>\n<testcase"), &report), Truncated, 2, 0},
+		{"stream truncated", "result.json", fmt.Errorf("decode: %w", io.ErrUnexpectedEOF), Truncated, 0, 0},
+		{"unreadable", "missing.json", decode("missing.json", &result), Unreadable, 0, 0},
+		{"other", "result.json", errors.New("result without uuid"), Unsupported, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := Skip(filepath.Join("testdata", tt.file), tt.err)
+			if d.Kind != tt.kind || d.Line != tt.line || d.Column != tt.column || d.Severity != Error {
+				t.Errorf("%s %s at %d:%d, want %s %s at %d:%d", d.Severity, d.Kind, d.Line, d.Column,
+					Error, tt.kind, tt.line, tt.column)
+			}
+		})
+	}
+}
+
+func TestSummary(t *testing.T) {
+	d := Diagnostics{
+		{File: "a.json", Kind: Syntax, Severity: Error},
+		{File: "b.json", Kind: Truncated, Severity: Error},
+		{File: "c.json", Kind: Syntax, Severity: Error},
+		Warn("d.json", Unsupported, "steps of x could not be read"),
+	}
+	want := "3 files skipped: 2 syntax error, 1 truncated file, 1 warnings"
+	if got := d.Summary(); got != want {
+		t.Errorf("Summary() = %q, want %q", got, want)
+	}
+	if got := (Diagnostics{Warn("d.json", Unsupported, "")}).Summary(); got != "0 files skipped, 1 warnings" {
+		t.Errorf("Summary() = %q, want only warnings", got)
+	}
+	line := Diagnostic{File: "a.json", Line: 3, Column: 13, Reason: "invalid character", Severity: Error}
+	if got := line.String(); got != "error: a.json:3:13: invalid character" {
+		t.Errorf("String() = %q", got)
+	}
+}
+
+func TestFrom(t *testing.T) {
+	c := &Collector{}
+	if d, fatal := From(c.Err()); d != nil || fatal != nil {
+		t.Errorf("From(nil) = %v, %v, want nothing", d, fatal)
+	}
+	c.Add(Diagnostic{File: "b.json", Offset: 10, Severity: Error}, Diagnostic{File: "a.json", Severity: Error})
+	c.Add(Diagnostic{File: "b.json", Offset: 2, Severity: Warning})
+	d, fatal := From(fmt.Errorf("read: %w", c.Err()))
+	if fatal != nil {
+		t.Fatalf("diagnostics are fatal: %v", fatal)
+	}
+	// diagnostics are sorted by files and offsets
+	got := []string{}
+	for _, x := range d {
+		got = append(got, fmt.Sprintf("%s@%d", x.File, x.Offset))
+	}
+	if fmt.Sprint(got) != "[a.json@0 b.json@2 b.json@10]" || d.Errors() != 2 {
+		t.Errorf("diagnostics %v with %d errors, want sorted with 2 errors", got, d.Errors())
+	}
+
+	err := errors.New("folder not found")
+	if d, fatal := From(err); d != nil || fatal != err {
+		t.Errorf("From(%v) = %v, %v, want the fatal error", err, d, fatal)
+	}
+}
+
+func TestMalformed(t *testing.T) {
+	var v struct{}
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{json.Unmarshal([]byte(`{"a":`), &v), true},
+		{xml.Unmarshal([]byte(`<a>`), &v), true},
+		{&os.PathError{Op: "open", Path: "a.json", Err: os.ErrPermission}, true},
+		{errors.New("a.json is not a playwright report"), false},
+		{json.Unmarshal([]byte(`[1]`), &v), false},
+	}
+	for _, tt := range tests {
+		if got := Malformed(tt.err); got != tt.want {
+			t.Errorf("Malformed(%v) = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,364 @@
+package files
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// archiveExts are extensions of archives that can be read as results folders.
+var archiveExts = []string{".zip", ".tar", ".tar.gz", ".tgz"}
+
+// archive is the opened archive, its files are read on demand, see openArchive.
+// @property fsys - Files of the archive.
+// @property closer - Closes the archive file on disk.
+type archive struct {
+	fsys   fs.FS
+	closer io.Closer
+}
+
+var (
+	archivesMu sync.Mutex
+	archives   = map[string]*archive{}
+)
+
+// IsArchive checks if the path has an extension of the supported archive.
+func IsArchive(p string) bool {
+	lower := strings.ToLower(p)
+	for _, ext := range archiveExts {
+		if strings.HasSuffix(lower, ext) {
+			return true
+		}
+	}
+	return false
+}
+
+// splitArchive splits the path to the archive on disk and the slash separated path inside it,
+// for ex. `results.zip/allure-results/1-result.json` to `results.zip` and `allure-results/1-result.json`.
+// The inner path is `.` for the archive itself.
+func splitArchive(p string) (string, string, bool) {
+	p = filepath.Clean(p)
+	parts := strings.Split(p, string(filepath.Separator))
+	for i := range parts {
+		archivePath := strings.Join(parts[:i+1], string(filepath.Separator))
+		if archivePath == "" || !IsArchive(archivePath) {
+			continue
+		}
+		if info, err := os.Stat(archivePath); err != nil || !info.Mode().IsRegular() {
+			continue
+		}
+		inner := path.Join(parts[i+1:]...)
+		if inner == "" {
+			inner = "."
+		}
+		return archivePath, inner, true
+	}
+	return "", "", false
+}
+
+// openArchive returns the archive as a file system. Archives are opened once and files are read
+// from the disk on demand, only the index of files is kept in memory and nothing is extracted.
+// Gzip does not support random access, so gzipped tarballs are decompressed once to a temporary tar file.
+func openArchive(archivePath string) (fs.FS, error) {
+	archivesMu.Lock()
+	defer archivesMu.Unlock()
+	if a, ok := archives[archivePath]; ok {
+		return a.fsys, nil
+	}
+
+	var a *archive
+	var err error
+	lower := strings.ToLower(archivePath)
+	switch {
+	case strings.HasSuffix(lower, ".zip"):
+		var zr *zip.ReadCloser
+		if zr, err = zip.OpenReader(archivePath); err == nil {
+			a = &archive{fsys: zr, closer: zr}
+		}
+	case strings.HasSuffix(lower, ".tar"):
+		a, err = openTar(archivePath)
+	default:
+		a, err = openTarGz(archivePath)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error trying to read archive %s: %v", archivePath, err)
+	}
+	archives[archivePath] = a
+	return a.fsys, nil
+}
+
+// CloseArchives closes all opened archives and removes their temporary files.
+// Archives are opened again if their files are read later.
+func CloseArchives() error {
+	archivesMu.Lock()
+	defer archivesMu.Unlock()
+	var firstErr error
+	for p, a := range archives {
+		if err := a.closer.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+		delete(archives, p)
+	}
+	return firstErr
+}
+
+func openTar(archivePath string) (*archive, error) {
+	f, err := os.Open(archivePath)
+	if err != nil {
+		return nil, err
+	}
+	fsys, err := indexTar(f)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	return &archive{fsys: fsys, closer: f}, nil
+}
+
+// openTarGz decompresses the tarball to a temporary file and indexes it like a tar archive.
+// The temporary file is removed right away where the system allows it, so it is not left behind
+// if the process exits without closing archives.
+func openTarGz(archivePath string) (*archive, error) {
+	src, err := os.Open(archivePath)
+	if err != nil {
+		return nil, err
+	}
+	defer src.Close()
+	gz, err := gzip.NewReader(src)
+	if err != nil {
+		return nil, err
+	}
+	tmp, err := os.CreateTemp("", "test-inspector-*.tar")
+	if err != nil {
+		return nil, err
+	}
+	removed := os.Remove(tmp.Name()) == nil
+	closer := &tempFile{File: tmp, removed: removed}
+	if _, err = io.Copy(tmp, gz); err != nil {
+		closer.Close()
+		return nil, err
+	}
+	if _, err = tmp.Seek(0, io.SeekStart); err != nil {
+		closer.Close()
+		return nil, err
+	}
+	fsys, err := indexTar(tmp)
+	if err != nil {
+		closer.Close()
+		return nil, err
+	}
+	return &archive{fsys: fsys, closer: closer}, nil
+}
+
+// tempFile removes the temporary file on close if it could not be removed while it was open.
+type tempFile struct {
+	*os.File
+	removed bool
+}
+
+func (f *tempFile) Close() error {
+	err := f.File.Close()
+	if !f.removed {
+		if rmErr := os.Remove(f.Name()); err == nil {
+			err = rmErr
+		}
+	}
+	return err
+}
+
+// indexTar reads headers of the tar file and remembers where the data of every regular file starts.
+// The tar reader reads headers block by block without buffering, so the position of the file
+// right after the header is the start of its data.
+func indexTar(f readSeekerAt) (*tarFS, error) {
+	fsys := &tarFS{
+		r:        f,
+		entries:  map[string]*tarEntry{".": {name: ".", dir: true, mode: fs.ModeDir | 0o755}},
+		children: map[string][]string{},
+	}
+	tr := tar.NewReader(f)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return fsys, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		name := path.Clean(strings.TrimPrefix(header.Name, "/"))
+		if !fs.ValidPath(name) || name == "." {
+			continue
+		}
+		switch header.Typeflag {
+		case tar.TypeDir:
+			fsys.addDir(name, header.ModTime)
+		case tar.TypeReg:
+			offset, err := f.Seek(0, io.SeekCurrent)
+			if err != nil {
+				return nil, err
+			}
+			fsys.addDir(path.Dir(name), time.Time{})
+			if e, ok := fsys.entries[name]; ok && e.dir {
+				continue
+			} else if !ok {
+				fsys.children[path.Dir(name)] = append(fsys.children[path.Dir(name)], name)
+			}
+			// the last copy of the file wins like on extraction
+			fsys.entries[name] = &tarEntry{name: name, offset: offset, size: header.Size,
+				mode: fs.FileMode(header.Mode).Perm(), modTime: header.ModTime}
+		}
+	}
+}
+
+type readSeekerAt interface {
+	io.ReadSeeker
+	io.ReaderAt
+}
+
+// tarFS is the file system of the indexed tar file, files are read by offsets.
+// @property r - The tar file.
+// @property entries - Files and folders by their paths.
+// @property children - Paths of entries of every folder.
+type tarFS struct {
+	r        io.ReaderAt
+	entries  map[string]*tarEntry
+	children map[string][]string
+}
+
+// tarEntry is a file or a folder of the tar file.
+type tarEntry struct {
+	name    string
+	dir     bool
+	offset  int64
+	size    int64
+	mode    fs.FileMode
+	modTime time.Time
+}
+
+func (t *tarFS) addDir(name string, modTime time.Time) {
+	for name != "." {
+		if e, ok := t.entries[name]; ok {
+			if !modTime.IsZero() {
+				e.modTime = modTime
+			}
+			return
+		}
+		t.entries[name] = &tarEntry{name: name, dir: true, mode: fs.ModeDir | 0o755, modTime: modTime}
+		parent := path.Dir(name)
+		t.children[parent] = append(t.children[parent], name)
+		name, modTime = parent, time.Time{}
+	}
+}
+
+// Open opens the file or the folder of the tar file.
+func (t *tarFS) Open(name string) (fs.File, error) {
+	e, err := t.entry("open", name)
+	if err != nil {
+		return nil, err
+	}
+	if e.dir {
+		entries, _ := t.ReadDir(name)
+		return &tarDir{entry: e, entries: entries}, nil
+	}
+	return &tarFile{entry: e, SectionReader: io.NewSectionReader(t.r, e.offset, e.size)}, nil
+}
+
+// Stat returns the info of the file or the folder without opening it.
+func (t *tarFS) Stat(name string) (fs.FileInfo, error) {
+	e, err := t.entry("stat", name)
+	if err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// ReadDir returns entries of the folder sorted by names.
+func (t *tarFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	e, err := t.entry("readdir", name)
+	if err != nil {
+		return nil, err
+	}
+	if !e.dir {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrInvalid}
+	}
+	names := append([]string{}, t.children[name]...)
+	sort.Strings(names)
+	entries := make([]fs.DirEntry, 0, len(names))
+	for _, n := range names {
+		entries = append(entries, fs.FileInfoToDirEntry(t.entries[n]))
+	}
+	return entries, nil
+}
+
+func (t *tarFS) entry(op, name string) (*tarEntry, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: op, Path: name, Err: fs.ErrInvalid}
+	}
+	e, ok := t.entries[name]
+	if !ok {
+		return nil, &fs.PathError{Op: op, Path: name, Err: fs.ErrNotExist}
+	}
+	return e, nil
+}
+
+func (e *tarEntry) Name() string       { return path.Base(e.name) }
+func (e *tarEntry) Size() int64        { return e.size }
+func (e *tarEntry) ModTime() time.Time { return e.modTime }
+func (e *tarEntry) IsDir() bool        { return e.dir }
+func (e *tarEntry) Sys() interface{}   { return nil }
+
+func (e *tarEntry) Mode() fs.FileMode {
+	if e.dir {
+		return fs.ModeDir | e.mode.Perm()
+	}
+	return e.mode
+}
+
+// tarFile is the opened file of the tar file.
+type tarFile struct {
+	*io.SectionReader
+	entry *tarEntry
+}
+
+func (f *tarFile) Stat() (fs.FileInfo, error) { return f.entry, nil }
+func (f *tarFile) Close() error               { return nil }
+
+// tarDir is the opened folder of the tar file.
+type tarDir struct {
+	entry   *tarEntry
+	entries []fs.DirEntry
+	read    int
+}
+
+func (d *tarDir) Stat() (fs.FileInfo, error) { return d.entry, nil }
+func (d *tarDir) Close() error               { return nil }
+
+func (d *tarDir) Read([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: d.entry.name, Err: fs.ErrInvalid}
+}
+
+// ReadDir returns the next n entries of the folder, or all remaining ones if n <= 0.
+func (d *tarDir) ReadDir(n int) ([]fs.DirEntry, error) {
+	rest := d.entries[d.read:]
+	if n <= 0 {
+		d.read = len(d.entries)
+		return rest, nil
+	}
+	if len(rest) == 0 {
+		return nil, io.EOF
+	}
+	if n > len(rest) {
+		n = len(rest)
+	}
+	d.read += n
+	return rest[:n], nil
+}
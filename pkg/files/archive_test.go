@@ -0,0 +1,130 @@
+package files
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"testing/fstest"
+)
+
+var archiveFiles = map[string]string{
+	"allure-results/1-result.json":        `{"name":"a"}`,
+	"allure-results/nested/2-result.json": `{"name":"b"}`,
+	"junit.xml":                           `<testsuites/>`,
+}
+
+func writeTar(t *testing.T, gz bool) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	var gzw *gzip.Writer
+	tw := tar.NewWriter(&buf)
+	if gz {
+		gzw = gzip.NewWriter(&buf)
+		tw = tar.NewWriter(gzw)
+	}
+	names := make([]string, 0, len(archiveFiles))
+	for name := range archiveFiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	if err := tw.WriteHeader(&tar.Header{Name: "allure-results/", Typeflag: tar.TypeDir, Mode: 0o755}); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range names {
+		data := archiveFiles[name]
+		if err := tw.WriteHeader(&tar.Header{Name: name, Typeflag: tar.TypeReg, Mode: 0o644, Size: int64(len(data))}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(data)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if gzw != nil {
+		if err := gzw.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return buf.Bytes()
+}
+
+func writeZip(t *testing.T) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, data := range archiveFiles {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err = w.Write([]byte(data)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestArchives(t *testing.T) {
+	tests := []struct {
+		name string
+		data func(t *testing.T) []byte
+	}{
+		{"results.tar", func(t *testing.T) []byte { return writeTar(t, false) }},
+		{"results.tar.gz", func(t *testing.T) []byte { return writeTar(t, true) }},
+		{"results.tgz", func(t *testing.T) []byte { return writeTar(t, true) }},
+		{"results.zip", writeZip},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer CloseArchives()
+			archivePath := filepath.Join(t.TempDir(), tt.name)
+			if err := os.WriteFile(archivePath, tt.data(t), 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			fsys, err := openArchive(archivePath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err = fstest.TestFS(fsys, "allure-results/1-result.json",
+				"allure-results/nested/2-result.json", "junit.xml"); err != nil {
+				t.Fatal(err)
+			}
+
+			list, err := List(filepath.Join(archivePath, "allure-results"), ".json")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(list) != 2 {
+				t.Fatalf("List() = %v, want 2 json files", list)
+			}
+			if !IsDir(archivePath) || IsDir(filepath.Join(archivePath, "junit.xml")) {
+				t.Fatal("IsDir() is wrong for the archive or the file inside it")
+			}
+
+			// archives are opened again after they are released
+			if err = CloseArchives(); err != nil {
+				t.Fatal(err)
+			}
+			for name, want := range archiveFiles {
+				data, err := ReadFile(filepath.Join(archivePath, filepath.FromSlash(name)))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if string(data) != want {
+					t.Errorf("ReadFile(%s) = %s, want %s", name, data, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,167 @@
+// Package files contains helpers to find and read report files in the results path.
+// The results path can be a file, a folder or a `.zip`, `.tar`, `.tar.gz`, `.tgz` archive,
+// files inside archives are addressed as `results.zip/path/in/archive.json`.
+package files
+
+import (
+	"encoding/xml"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+)
+
+// List returns the results path itself if it is a file, or all files with one of the extensions
+// in the results folder or archive and its subfolders. All files are returned if no extensions are passed.
+func List(resultsPath string, exts ...string) ([]string, error) {
+	if archivePath, inner, ok := splitArchive(resultsPath); ok {
+		return listArchive(archivePath, inner, exts)
+	}
+	info, err := os.Stat(resultsPath)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return []string{resultsPath}, nil
+	}
+	files := []string{}
+	err = filepath.Walk(resultsPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.Mode().IsRegular() && hasExt(path, exts) {
+			files = append(files, path)
+		}
+		return nil
+	})
+	return files, err
+}
+
+func listArchive(archivePath, inner string, exts []string) ([]string, error) {
+	fsys, err := openArchive(archivePath)
+	if err != nil {
+		return nil, err
+	}
+	info, err := fs.Stat(fsys, inner)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return []string{filepath.Join(archivePath, filepath.FromSlash(inner))}, nil
+	}
+	files := []string{}
+	err = fs.WalkDir(fsys, inner, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.Type().IsRegular() && hasExt(p, exts) {
+			files = append(files, filepath.Join(archivePath, filepath.FromSlash(p)))
+		}
+		return nil
+	})
+	return files, err
+}
+
+func hasExt(p string, exts []string) bool {
+	if len(exts) == 0 {
+		return true
+	}
+	for _, ext := range exts {
+		if path.Ext(filepath.ToSlash(p)) == ext {
+			return true
+		}
+	}
+	return false
+}
+
+// IsDir checks if the results path is a folder or an archive, or a folder inside the archive.
+func IsDir(resultsPath string) bool {
+	if archivePath, inner, ok := splitArchive(resultsPath); ok {
+		fsys, err := openArchive(archivePath)
+		if err != nil {
+			return false
+		}
+		info, err := fs.Stat(fsys, inner)
+		return err == nil && info.IsDir()
+	}
+	info, err := os.Stat(resultsPath)
+	return err == nil && info.IsDir()
+}
+
+// Open opens the file on disk or inside the archive for reading.
+func Open(p string) (io.ReadCloser, error) {
+	if archivePath, inner, ok := splitArchive(p); ok {
+		fsys, err := openArchive(archivePath)
+		if err != nil {
+			return nil, err
+		}
+		return fsys.Open(inner)
+	}
+	return os.Open(p)
+}
+
+// Stat returns the info of the file on disk or inside the archive without reading it.
+func Stat(p string) (fs.FileInfo, error) {
+	if archivePath, inner, ok := splitArchive(p); ok {
+		fsys, err := openArchive(archivePath)
+		if err != nil {
+			return nil, err
+		}
+		return fs.Stat(fsys, inner)
+	}
+	return os.Stat(p)
+}
+
+// ReadFile reads the whole file on disk or inside the archive.
+func ReadFile(p string) ([]byte, error) {
+	if archivePath, inner, ok := splitArchive(p); ok {
+		fsys, err := openArchive(archivePath)
+		if err != nil {
+			return nil, err
+		}
+		return fs.ReadFile(fsys, inner)
+	}
+	return os.ReadFile(p)
+}
+
+// XMLRoot returns the name of the root element of the xml file or an empty string if it is not xml.
+func XMLRoot(p string) string {
+	f, err := Open(p)
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+	return xmlRoot(f)
+}
+
+func xmlRoot(r io.Reader) string {
+	decoder := xml.NewDecoder(r)
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return ""
+		}
+		if start, ok := token.(xml.StartElement); ok {
+			return start.Name.Local
+		}
+	}
+}
+
+// HasXMLRoot checks if the results path is an xml file with one of the root elements
+// or a folder containing such file.
+func HasXMLRoot(resultsPath string, roots ...string) bool {
+	files, err := List(resultsPath, ".xml", ".trx")
+	if err != nil {
+		return false
+	}
+	for _, f := range files {
+		root := XMLRoot(f)
+		for _, r := range roots {
+			if root == r {
+				return true
+			}
+		}
+	}
+	return false
+}
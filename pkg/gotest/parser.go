@@ -0,0 +1,248 @@
+package gotest
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"test-inspector/pkg/diag"
+	"test-inspector/pkg/files"
+	"test-inspector/pkg/models"
+	"test-inspector/pkg/supatms"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// maxLineSize is the max size of a single test2json event, long outputs are split by go test anyway.
+const maxLineSize = 4 * 1024 * 1024
+
+// Parser is a report parser for `go test -json` output.
+type Parser struct{}
+
+// Name returns the report type of go test json reports.
+func (Parser) Name() string {
+	return "gotest"
+}
+
+// Detect checks if the path is a go test json file or a folder containing one.
+func (Parser) Detect(resultsPath string) bool {
+	reports, err := files.List(resultsPath, ".json", ".jsonl")
+	if err != nil {
+		return false
+	}
+	for _, f := range reports {
+		if isEventsFile(f) {
+			return true
+		}
+	}
+	return false
+}
+
+// ReadResults reads go test json report, see ReadResults.
+func (Parser) ReadResults(resultsPath string) (map[uuid.UUID]models.SupaResult, error) {
+	return ReadResults(resultsPath)
+}
+
+// event is a single line of the test2json output.
+type event struct {
+	Time    time.Time `json:"Time"`
+	Action  string    `json:"Action"`
+	Package string    `json:"Package"`
+	Test    string    `json:"Test"`
+	Elapsed float64   `json:"Elapsed"`
+	Output  string    `json:"Output"`
+}
+
+// test is a go test or subtest built from test2json events.
+type test struct {
+	name     string
+	status   string
+	start    time.Time
+	elapsed  float64
+	output   []string
+	subtests []*test
+	byName   map[string]*test
+}
+
+func newTest(name string) *test {
+	return &test{name: name, status: "unknown", byName: map[string]*test{}}
+}
+
+func (t *test) subtest(name string) *test {
+	if s, ok := t.byName[name]; ok {
+		return s
+	}
+	s := newTest(name)
+	t.byName[name] = s
+	t.subtests = append(t.subtests, s)
+	return s
+}
+
+// ReadResults reads go test json output file or all such files in the folder
+// and returns them as a map of SupaResults
+func ReadResults(resultsPath string) (map[uuid.UUID]models.SupaResult, error) {
+	reports, err := files.List(resultsPath, ".json", ".jsonl")
+	if err != nil {
+		return nil, err
+	}
+
+	results := map[uuid.UUID]models.SupaResult{}
+	diagnostics := &diag.Collector{}
+	for _, f := range reports {
+		if !isEventsFile(f) {
+			continue
+		}
+		packages, err := parseFile(f)
+		if err != nil {
+			diagnostics.Add(diag.Skip(f, err))
+			continue
+		}
+		for _, pkg := range packages {
+			for _, t := range pkg.subtests {
+				ar := convertTest(pkg.name, t)
+				steps, err := supatms.StepsJSON(ar)
+				if err != nil {
+					diagnostics.Add(diag.Warn(f, diag.Unsupported,
+						fmt.Sprintf("steps of %s could not be read: %v", ar.Name, err)))
+				}
+				res := supatms.ToResult(0, *ar, steps)
+				results[res.ID] = res
+			}
+		}
+	}
+	return results, diagnostics.Err()
+}
+
+// parseFile reads test2json events and returns tests grouped by packages.
+func parseFile(path string) ([]*test, error) {
+	f, err := files.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	root := newTest("")
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), maxLineSize)
+	for scanner.Scan() {
+		var e event
+		// go test mixes plain build output with json events, skip everything that is not an event
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil || e.Action == "" || e.Test == "" {
+			continue
+		}
+		t := root.subtest(e.Package)
+		for _, name := range strings.Split(e.Test, "/") {
+			t = t.subtest(name)
+		}
+		applyEvent(t, e)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return root.subtests, nil
+}
+
+func applyEvent(t *test, e event) {
+	switch e.Action {
+	case "run":
+		t.start = e.Time
+	case "output":
+		t.output = append(t.output, strings.TrimRight(e.Output, "\n"))
+	case "pass":
+		t.status = "passed"
+		t.elapsed = e.Elapsed
+	case "fail":
+		t.status = "failed"
+		t.elapsed = e.Elapsed
+	case "skip":
+		t.status = "skipped"
+		t.elapsed = e.Elapsed
+	}
+}
+
+func convertTest(pkg string, t *test) *models.AllureResult {
+	start, stop := timings(t)
+	fullName := pkg + "." + t.name
+	output := strings.Join(t.output, "\n")
+	return &models.AllureResult{
+		Name:   t.name,
+		Status: t.status,
+		StatusDetails: &models.StatusDetails{
+			Trace: &output,
+		},
+		Steps:    convertSubtests(t),
+		Start:    start,
+		Stop:     stop,
+		UUID:     uuid.New(),
+		FullName: &fullName,
+		Labels: []*models.Label{
+			{
+				Name:  "suite",
+				Value: t.name,
+			},
+			{
+				Name:  "parentSuite",
+				Value: pkg,
+			},
+			{
+				Name:  "framework",
+				Value: "gotest",
+			},
+			{
+				Name:  "language",
+				Value: "go",
+			},
+		},
+	}
+}
+
+func convertSubtests(t *test) []*models.Step {
+	steps := []*models.Step{}
+	for _, s := range t.subtests {
+		s := s
+		start, stop := timings(s)
+		output := strings.Join(s.output, "\n")
+		steps = append(steps, &models.Step{
+			Name:   s.name,
+			Status: &s.status,
+			StatusDetails: &models.StatusDetails{
+				Trace: &output,
+			},
+			Steps: convertSubtests(s),
+			Start: start,
+			Stop:  stop,
+		})
+	}
+	return steps
+}
+
+// timings returns start and stop of the test in milliseconds since the epoch.
+func timings(t *test) (int64, int64) {
+	var start int64
+	if !t.start.IsZero() {
+		start = t.start.UnixMilli()
+	}
+	return start, start + int64(t.elapsed*1000)
+}
+
+// isEventsFile checks if the first json line of the file is a test2json event.
+func isEventsFile(path string) bool {
+	f, err := files.Open(path)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), maxLineSize)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if !strings.HasPrefix(line, "{") {
+			continue
+		}
+		var e event
+		return json.Unmarshal([]byte(line), &e) == nil && e.Action != ""
+	}
+	return false
+}
@@ -0,0 +1,94 @@
+package gotest
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"test-inspector/internal/parsertest"
+	"test-inspector/pkg/diag"
+	"testing"
+	"time"
+)
+
+func TestReadResults(t *testing.T) {
+	start := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC).UnixMilli()
+	tests := []struct {
+		name        string
+		want        parsertest.Want
+		start, stop int64
+		trace       string
+	}{
+		{
+			name: "TestSignIn",
+			want: parsertest.Want{
+				FullName: "example.com/auth.TestSignIn", ParentSuite: "example.com/auth", Suite: "TestSignIn",
+				Status: "failed",
+				Steps: []string{
+					"valid: passed",
+					"wrong_password: failed",
+					"  reports_error: failed",
+				},
+			},
+			start: start, stop: start + 500,
+			trace: "=== RUN   TestSignIn",
+		},
+		{
+			name: "TestSignOut",
+			want: parsertest.Want{
+				FullName: "example.com/auth.TestSignOut", ParentSuite: "example.com/auth", Suite: "TestSignOut",
+				Status: "skipped", Steps: []string{},
+			},
+			start: start + 500, stop: start + 500,
+			trace: "auth_test.go:60: needs a server",
+		},
+		{
+			name: "TestUpload",
+			want: parsertest.Want{
+				FullName: "example.com/storage.TestUpload", ParentSuite: "example.com/storage", Suite: "TestUpload",
+				Status: "passed", Steps: []string{},
+			},
+			start: start, stop: start + 1250,
+		},
+	}
+	results, d := parsertest.Read(t, ReadResults, "testdata")
+	if len(d) != 0 {
+		t.Errorf("diagnostics %v, other json files are skipped silently", d)
+	}
+	if len(results) != len(tests) {
+		t.Fatalf("results %v, want %d", results.Names(), len(tests))
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := results.Find(t, tt.name)
+			parsertest.Check(t, r, tt.want)
+			if r.Start != tt.start || r.Stop != tt.stop {
+				t.Errorf("start %d, stop %d, want %d, %d", r.Start, r.Stop, tt.start, tt.stop)
+			}
+			if !strings.Contains(*r.StatusDetails.Trace, tt.trace) {
+				t.Errorf("output %q does not contain %q", *r.StatusDetails.Trace, tt.trace)
+			}
+		})
+	}
+}
+
+func TestReadResultsMalformed(t *testing.T) {
+	dir := t.TempDir()
+	event := `{"Action":"run","Package":"example.com/auth","Test":"TestSignIn"}` + "\n"
+	huge := `{"Action":"output","Output":"` + strings.Repeat("x", maxLineSize) + `"}` + "\n"
+	if err := os.WriteFile(filepath.Join(dir, "huge.json"), []byte(event+huge), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(filepath.Join("testdata", "go-test.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = os.WriteFile(filepath.Join(dir, "go-test.json"), data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	results, d := parsertest.Read(t, ReadResults, dir)
+	parsertest.Malformed(t, d, "huge.json", diag.Unsupported)
+	if len(results) != 3 {
+		t.Errorf("results %v, want tests of the well-formed file", results.Names())
+	}
+}
@@ -0,0 +1,195 @@
+package inspect
+
+import (
+	"strings"
+	"test-inspector/pkg/models"
+
+	"github.com/google/uuid"
+)
+
+// Kind is the kind of the difference between the reference run and the local run.
+type Kind string
+
+const (
+	// MissingTest is a reference test without the local result.
+	MissingTest Kind = "missing-test"
+	// StepMismatch is a local test with other steps than the reference test, see StepDiff.
+	StepMismatch Kind = "step-mismatch"
+	// StepCountMismatch is a step mismatch of a local test with another number of steps than the reference test,
+	// expected and actual values of the finding are numbers of steps with nested ones.
+	StepCountMismatch Kind = "step-count-mismatch"
+	// ExtraTest is a local result without the reference test.
+	ExtraTest Kind = "extra-test"
+	// StatusRegression is a local test with another status than the reference test, see StatusRules.
+	StatusRegression Kind = "status-regression"
+	// AmbiguousMatch is a reference test without a single local result that matches it best by any strategy,
+	// see Matcher.
+	AmbiguousMatch Kind = "ambiguous-match"
+)
+
+// Severity is how bad the finding is, errors fail the inspection.
+type Severity string
+
+const (
+	// Error is a finding that fails the inspection.
+	Error Severity = "error"
+	// Warning is a finding that is reported but does not fail the inspection.
+	Warning Severity = "warning"
+)
+
+// Identity identifies the test of the reference run or the local run.
+// @property ID - The ID of the result.
+// @property {string} Name - The name of the test.
+// @property {string} FullName - The full name of the test.
+// @property {string} Feature - The feature of the test.
+// @property {string} ParentSuite - The parent suite of the test.
+// @property {string} Suite - The suite of the test.
+// @property {string} SubSuite - The sub suite of the test.
+// @property {string} Status - The status of the test.
+type Identity struct {
+	ID          uuid.UUID `json:"id"`
+	Name        string    `json:"name"`
+	FullName    string    `json:"fullName,omitempty"`
+	Feature     string    `json:"feature,omitempty"`
+	ParentSuite string    `json:"parentSuite,omitempty"`
+	Suite       string    `json:"suite,omitempty"`
+	SubSuite    string    `json:"subSuite,omitempty"`
+	Status      string    `json:"status,omitempty"`
+}
+
+func identityOf(r models.SupaResult) *Identity {
+	return &Identity{
+		ID:          r.ID,
+		Name:        r.Name,
+		FullName:    r.FullName,
+		Feature:     r.Feature,
+		ParentSuite: r.ParentSuite,
+		Suite:       r.Suite,
+		SubSuite:    r.SubSuite,
+		Status:      r.Status,
+	}
+}
+
+// testName returns the name of the test with its suite.
+func testName(t *Identity) string {
+	if t == nil {
+		return ""
+	}
+	if suite := className(t); suite != "" {
+		return suite + ": " + t.Name
+	}
+	return t.Name
+}
+
+// candidateName returns the name of the test with its full name or ID,
+// so candidates of ambiguous matches with the same name are told apart.
+func candidateName(t *Identity) string {
+	if t.FullName != "" {
+		return testName(t) + " (" + t.FullName + ")"
+	}
+	return testName(t) + " (" + t.ID.String() + ")"
+}
+
+// className joins suites of the test like junit reporters do.
+func className(t *Identity) string {
+	names := []string{}
+	for _, name := range []string{t.ParentSuite, t.Suite, t.SubSuite} {
+		if name != "" {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return t.Feature
+	}
+	return strings.Join(names, ".")
+}
+
+// Group returns the feature and suites of the test, extra tests are grouped by them.
+func Group(t *Identity) string {
+	names := []string{}
+	for _, name := range []string{t.Feature, t.ParentSuite, t.Suite, t.SubSuite} {
+		if name != "" && (len(names) == 0 || names[len(names)-1] != name) {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return "no suite"
+	}
+	return strings.Join(names, " > ")
+}
+
+// Finding is a single difference between the reference run and the local run.
+// @property {Kind} Kind - The kind of the difference.
+// @property {Severity} Severity - The severity of the difference.
+// @property Template - The reference test, nil for extra tests.
+// @property Local - The local test, nil for missing tests.
+// @property {StepDiff} Diff - Every difference of steps for step mismatches and step count mismatches.
+// @property {[]*Identity} Candidates - Local tests of ambiguous matches.
+// @property {string} Expected - The value in the reference test, for ex. the status or the number of steps.
+// @property {string} Actual - The value in the local test.
+// @property {string} Message - The description of the difference.
+type Finding struct {
+	Kind       Kind        `json:"kind"`
+	Severity   Severity    `json:"severity"`
+	Template   *Identity   `json:"template,omitempty"`
+	Local      *Identity   `json:"local,omitempty"`
+	Diff       StepDiff    `json:"diff,omitempty"`
+	Candidates []*Identity `json:"candidates,omitempty"`
+	Expected   string      `json:"expected,omitempty"`
+	Actual     string      `json:"actual,omitempty"`
+	Message    string      `json:"message"`
+}
+
+// Report is the result of the inspection rendered by one of the formats, see Render.
+// @property {[]string} LocalPaths - Results paths of the local run.
+// @property {int} LocalCount - The number of local results.
+// @property {int} ReferenceCount - The number of reference results.
+// @property {[]*Identity} References - Reference tests in the order of the reference run.
+// @property {[]Finding} Findings - Findings ordered by reference tests.
+// @property {int} Errors - The number of error findings.
+// @property {int} Warnings - The number of warning findings.
+// @property {[]string} Notes - Problems that prevented some checks, for ex. unreadable steps.
+type Report struct {
+	LocalPaths     []string    `json:"localPaths"`
+	LocalCount     int         `json:"localCount"`
+	ReferenceCount int         `json:"referenceCount"`
+	References     []*Identity `json:"-"`
+	Findings       []Finding   `json:"findings"`
+	Errors         int         `json:"errors"`
+	Warnings       int         `json:"warnings"`
+	Notes          []string    `json:"notes,omitempty"`
+}
+
+func (r *Report) add(findings ...Finding) {
+	for _, f := range findings {
+		r.Findings = append(r.Findings, f)
+		if f.Severity == Error {
+			r.Errors++
+		} else {
+			r.Warnings++
+		}
+	}
+}
+
+// Extras returns extra test findings grouped by features and suites of tests, see Group.
+// Groups are in the order of the report.
+func (r *Report) Extras() ([]string, map[string][]Finding) {
+	groups := []string{}
+	extras := map[string][]Finding{}
+	for _, f := range r.Findings {
+		if f.Kind != ExtraTest {
+			continue
+		}
+		group := Group(f.Local)
+		if _, ok := extras[group]; !ok {
+			groups = append(groups, group)
+		}
+		extras[group] = append(extras[group], f)
+	}
+	return groups, extras
+}
+
+// Passed checks if the inspection found nothing.
+func (r *Report) Passed() bool {
+	return r.Errors == 0 && r.Warnings == 0
+}
@@ -0,0 +1,215 @@
+// Package inspect compares results of the local run with the reference run of the version.
+// Every difference is a finding of the report, the report is rendered by one of the formats, see Render.
+package inspect
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"test-inspector/pkg/models"
+	"test-inspector/pkg/workers"
+
+	"github.com/google/uuid"
+)
+
+// Options of the inspection.
+// @property Statuses - Severities of status pairs, default ones are used if it is nil.
+// @property {Severity} Extras - The severity of local tests without the reference test, warning by default.
+// @property Strategies - Strategies to find local results of reference tests, DefaultStrategies if it is nil.
+type Options struct {
+	Statuses   *StatusRules
+	Extras     Severity
+	Strategies []Strategy
+}
+
+// check is the outcome of the comparison of a single reference test.
+type check struct {
+	findings []Finding
+	note     string
+}
+
+// Compare finds the local result of every reference test and compares their statuses and steps.
+// Local results without the reference test are reported after them as extra tests.
+// Findings are ordered by reference tests, so the report does not depend on the order of checks.
+func Compare(templates []models.SupaResult, results map[uuid.UUID]models.SupaResult, opts Options) *Report {
+	report := &Report{LocalCount: len(results), ReferenceCount: len(templates)}
+	strategies := opts.Strategies
+	if strategies == nil {
+		strategies, _ = CompileStrategies(DefaultStrategies)
+	}
+	matches := NewMatcher(strategies, results).MatchAll(templates)
+	checks := make([]check, len(templates))
+	workers.Run(0, len(templates), func(i int) {
+		checks[i] = compareTest(templates[i], matches[i], opts)
+	})
+	matched := map[uuid.UUID]bool{}
+	for i, t := range templates {
+		report.References = append(report.References, identityOf(t))
+		report.add(checks[i].findings...)
+		if checks[i].note != "" {
+			report.Notes = append(report.Notes, checks[i].note)
+		}
+		if matches[i].Result != nil {
+			matched[matches[i].Result.ID] = true
+		}
+		// candidates of ambiguous matches are already reported with the reference test
+		for _, c := range matches[i].Candidates {
+			matched[c.ID] = true
+		}
+	}
+	report.add(extraTests(results, matched, opts.Extras)...)
+	return report
+}
+
+// compareTest returns findings of the reference test and its local result.
+// Steps are compared only if both tests passed, steps of failed tests are usually cut short.
+func compareTest(t models.SupaResult, m Match, opts Options) check {
+	if m.Ambiguous() {
+		candidates := make([]*Identity, 0, len(m.Candidates))
+		for _, c := range m.Candidates {
+			candidates = append(candidates, identityOf(c))
+		}
+		return check{findings: []Finding{{
+			Kind:       AmbiguousMatch,
+			Severity:   Error,
+			Template:   identityOf(t),
+			Candidates: candidates,
+			Message:    ambiguousMessage(t, m),
+		}}}
+	}
+	r := m.Result
+	if r == nil {
+		return check{findings: []Finding{{
+			Kind:     MissingTest,
+			Severity: Error,
+			Template: identityOf(t),
+			Message:  fmt.Sprintf("no test result found for template: %s - %s", t.Name, t.ParentSuite),
+		}}}
+	}
+	res := check{}
+	if f := compareStatus(t, *r, opts.Statuses); f != nil {
+		res.findings = []Finding{*f}
+		return res
+	}
+	if t.Status != "passed" || r.Status != "passed" || t.Steps == "" || r.Steps == "" || t.Steps == r.Steps {
+		return res
+	}
+	var templateSteps []*models.StepContainer
+	if err := json.Unmarshal([]byte(t.Steps), &templateSteps); err != nil {
+		res.note = fmt.Sprintf("error when unmarshal template steps of %s: %v", t.Name, err)
+		return res
+	}
+	var resultSteps []*models.StepContainer
+	if err := json.Unmarshal([]byte(r.Steps), &resultSteps); err != nil {
+		res.note = fmt.Sprintf("error when unmarshal result steps of %s: %v", r.Name, err)
+		return res
+	}
+	diff := diffSteps(templateSteps, resultSteps, []string{})
+	if !diff.Changed() {
+		return res
+	}
+	f := Finding{
+		Kind:     StepMismatch,
+		Severity: Warning,
+		Template: identityOf(t),
+		Local:    identityOf(*r),
+		Diff:     diff,
+		Message:  fmt.Sprintf("steps in result differ from template - %s: %s", t.Name, diff.Summary()),
+	}
+	// steps were added or lost, not only renamed or moved
+	if expected, actual := countSteps(templateSteps), countSteps(resultSteps); expected != actual {
+		f.Kind = StepCountMismatch
+		f.Expected, f.Actual = strconv.Itoa(expected), strconv.Itoa(actual)
+		f.Message = fmt.Sprintf("number of steps in result (%d) differs from template (%d) - %s: %s",
+			actual, expected, t.Name, diff.Summary())
+	}
+	res.findings = []Finding{f}
+	return res
+}
+
+// countSteps returns the number of steps in the tree with nested ones.
+func countSteps(steps []*models.StepContainer) int {
+	n := len(steps)
+	for _, s := range steps {
+		n += countSteps(s.StepContainer)
+	}
+	return n
+}
+
+// extraTests returns findings of local results not matched by any reference test, ordered by groups.
+// They are often tests of behavior the reference does not have or tests with misspelled names.
+func extraTests(results map[uuid.UUID]models.SupaResult, matched map[uuid.UUID]bool,
+	severity Severity) []Finding {
+	if severity == "" {
+		severity = Warning
+	}
+	extras := []*Identity{}
+	for id, r := range results {
+		if !matched[id] {
+			extras = append(extras, identityOf(r))
+		}
+	}
+	sort.Slice(extras, func(i, j int) bool {
+		if gi, gj := Group(extras[i]), Group(extras[j]); gi != gj {
+			return gi < gj
+		}
+		if extras[i].Name != extras[j].Name {
+			return extras[i].Name < extras[j].Name
+		}
+		return extras[i].ID.String() < extras[j].ID.String()
+	})
+	findings := make([]Finding, 0, len(extras))
+	for _, e := range extras {
+		findings = append(findings, Finding{
+			Kind:     ExtraTest,
+			Severity: severity,
+			Local:    e,
+			Message:  fmt.Sprintf("no template found for test result: %s - %s", e.Name, Group(e)),
+		})
+	}
+	return findings
+}
+
+// ambiguousMessage describes why the reference test has no single local result.
+func ambiguousMessage(t models.SupaResult, m Match) string {
+	if len(m.Candidates) == 1 {
+		return fmt.Sprintf("test result matches several templates by %s - %s", m.Strategy, testName(identityOf(t)))
+	}
+	return fmt.Sprintf("%d test results match template by %s - %s", len(m.Candidates), m.Strategy,
+		testName(identityOf(t)))
+}
+
+// nolint:gocyclo // this is just trying to find a match for suite/subsuite/parentsuite
+func checkSuiteNames(template, result models.SupaResult) bool {
+	if (normalizeName(result.Suite) == normalizeName(template.Suite)) && (template.Suite != "") ||
+		(normalizeName(result.ParentSuite) == normalizeName(template.Suite)) && (template.Suite != "") ||
+		(normalizeName(result.SubSuite) == normalizeName(template.Suite)) && (template.Suite != "") ||
+		(normalizeName(result.Suite) == normalizeName(template.ParentSuite)) && (template.ParentSuite != "") ||
+		(normalizeName(result.ParentSuite) == normalizeName(template.ParentSuite)) && (template.ParentSuite != "") ||
+		(normalizeName(result.SubSuite) == normalizeName(template.ParentSuite)) && (template.ParentSuite != "") ||
+		(normalizeName(result.Suite) == normalizeName(template.SubSuite)) && (template.SubSuite != "") ||
+		(normalizeName(result.ParentSuite) == normalizeName(template.SubSuite)) && (template.SubSuite != "") ||
+		(normalizeName(result.SubSuite) == normalizeName(template.SubSuite)) && (template.SubSuite != "") {
+		return true
+	}
+	return false
+}
+
+var (
+	bracketsRe   = regexp.MustCompile(`\{.*\}`)
+	separatorsRe = regexp.MustCompile(`\s|_`)
+)
+
+func replaceAllSubstringsInBrackets(str string) string {
+	return bracketsRe.ReplaceAllString(str, "")
+}
+
+func normalizeName(str string) string {
+	s := separatorsRe.ReplaceAllString(str, "")
+	s = strings.ToLower(s)
+	s = strings.ReplaceAll(s, "test", "")
+	return s
+}
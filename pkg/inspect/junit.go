@@ -0,0 +1,115 @@
+package inspect
+
+import (
+	"encoding/xml"
+	"io"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// junitSuites is the junit xml report of the inspection, reference tests and extra tests are two suites.
+type junitSuites struct {
+	XMLName xml.Name      `xml:"testsuites"`
+	Name    string        `xml:"name,attr"`
+	Suites  []*junitSuite `xml:"testsuite"`
+}
+
+type junitSuite struct {
+	Name      string       `xml:"name,attr"`
+	Tests     int          `xml:"tests,attr"`
+	Failures  int          `xml:"failures,attr"`
+	TestCases []*junitCase `xml:"testcase"`
+}
+
+type junitCase struct {
+	Name      string          `xml:"name,attr"`
+	ClassName string          `xml:"classname,attr"`
+	Failures  []*junitFailure `xml:"failure"`
+	SystemOut *junitOutput    `xml:"system-out,omitempty"`
+}
+
+// junitOutput keeps line breaks of the text, chardata escapes them.
+type junitOutput struct {
+	Text string `xml:",cdata"`
+}
+
+type junitFailure struct {
+	Type    string `xml:"type,attr"`
+	Message string `xml:"message,attr"`
+	Text    string `xml:",cdata"`
+}
+
+// RenderJUnit writes the report as junit xml, every reference test is a test case.
+// Errors are failures of the test case, warnings are written to its output,
+// so CI fails only on what fails the inspection. Extra tests are test cases of a separate suite.
+func RenderJUnit(w io.Writer, r *Report) error {
+	suite := &junitSuite{Name: "test-inspector"}
+	cases := map[uuid.UUID]*junitCase{}
+	for _, t := range r.References {
+		c := &junitCase{Name: t.Name, ClassName: className(t)}
+		cases[t.ID] = c
+		suite.TestCases = append(suite.TestCases, c)
+	}
+	extras := &junitSuite{Name: "extra tests"}
+	for _, f := range r.Findings {
+		var c *junitCase
+		switch {
+		case f.Kind == ExtraTest:
+			c = &junitCase{Name: f.Local.Name, ClassName: className(f.Local)}
+			extras.TestCases = append(extras.TestCases, c)
+		case f.Template != nil:
+			c = cases[f.Template.ID]
+		}
+		if c == nil {
+			continue
+		}
+		if f.Severity == Warning {
+			if c.SystemOut == nil {
+				c.SystemOut = &junitOutput{}
+			}
+			c.SystemOut.Text += "[WARN]: " + f.Message + "\n"
+			if text := details(f); text != "" {
+				c.SystemOut.Text += text + "\n"
+			}
+			continue
+		}
+		c.Failures = append(c.Failures, &junitFailure{Type: string(f.Kind), Message: f.Message, Text: details(f)})
+	}
+	report := &junitSuites{Name: "test-inspector", Suites: []*junitSuite{suite}}
+	if len(extras.TestCases) > 0 {
+		report.Suites = append(report.Suites, extras)
+	}
+	for _, s := range report.Suites {
+		s.Tests = len(s.TestCases)
+		for _, c := range s.TestCases {
+			if len(c.Failures) > 0 {
+				s.Failures++
+			}
+		}
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// details describes what was expected, the diff of steps and candidates of ambiguous matches.
+func details(f Finding) string {
+	lines := []string{}
+	if f.Expected != "" || f.Actual != "" {
+		lines = append(lines, "expected: "+f.Expected, "actual: "+f.Actual)
+	}
+	lines = append(lines, f.Diff.Lines()...)
+	for _, c := range f.Candidates {
+		lines = append(lines, "candidate: "+candidateName(c))
+	}
+	return strings.Join(lines, "\n")
+}
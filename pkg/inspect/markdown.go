@@ -0,0 +1,98 @@
+package inspect
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// RenderMarkdown writes the report as markdown for comments of pull requests.
+func RenderMarkdown(w io.Writer, r *Report) error {
+	var b strings.Builder
+	b.WriteString("## Test Results comparison report\n\n")
+	fmt.Fprintf(&b, "**%d** test results found in local run (%s), **%d** in template run.\n\n",
+		r.LocalCount, strings.Join(r.LocalPaths, ", "), r.ReferenceCount)
+	if r.Passed() {
+		b.WriteString(":white_check_mark: All checks passed!\n")
+	} else {
+		fmt.Fprintf(&b, "**%d errors** and **%d warnings** found.\n", r.Errors, r.Warnings)
+	}
+
+	for _, severity := range []Severity{Error, Warning} {
+		findings := []Finding{}
+		for _, f := range r.Findings {
+			if f.Severity == severity && f.Kind != ExtraTest {
+				findings = append(findings, f)
+			}
+		}
+		if len(findings) == 0 {
+			continue
+		}
+		title := "Errors"
+		if severity == Warning {
+			title = "Warnings"
+		}
+		fmt.Fprintf(&b, "\n### %s\n\n", title)
+		b.WriteString("| Kind | Template test | Local test | Details | Expected | Actual |\n")
+		b.WriteString("| --- | --- | --- | --- | --- | --- |\n")
+		for _, f := range findings {
+			fmt.Fprintf(&b, "| %s | %s | %s | %s | %s | %s |\n",
+				f.Kind, cell(testName(f.Template)), cell(testName(f.Local)), cell(summary(f)),
+				cell(f.Expected), cell(f.Actual))
+		}
+	}
+
+	diffs := false
+	for _, f := range r.Findings {
+		if len(f.Diff) == 0 {
+			continue
+		}
+		if !diffs {
+			b.WriteString("\n### Step diffs\n")
+			diffs = true
+		}
+		fmt.Fprintf(&b, "\n**%s**\n\n```diff\n%s\n```\n", testName(f.Template), strings.Join(f.Diff.Lines(), "\n"))
+	}
+
+	groups, extras := r.Extras()
+	if len(groups) > 0 {
+		b.WriteString("\n### Test results not found in template run\n")
+	}
+	for _, group := range groups {
+		fmt.Fprintf(&b, "\n**%s**\n\n", group)
+		for _, f := range extras[group] {
+			mark := ":warning:"
+			if f.Severity == Error {
+				mark = ":x:"
+			}
+			fmt.Fprintf(&b, "- %s %s\n", mark, f.Local.Name)
+		}
+	}
+
+	if len(r.Notes) > 0 {
+		b.WriteString("\n### Notes\n\n")
+		for _, note := range r.Notes {
+			b.WriteString("- " + note + "\n")
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// summary counts changes of steps or lists candidates of ambiguous matches.
+func summary(f Finding) string {
+	if len(f.Candidates) == 0 {
+		return f.Diff.Summary()
+	}
+	names := make([]string, 0, len(f.Candidates))
+	for _, c := range f.Candidates {
+		names = append(names, candidateName(c))
+	}
+	return "candidates: " + strings.Join(names, ", ")
+}
+
+// cell escapes the text for the cell of the markdown table.
+func cell(s string) string {
+	s = strings.ReplaceAll(s, "|", `\|`)
+	return strings.ReplaceAll(s, "\n", " ")
+}
@@ -0,0 +1,322 @@
+package inspect
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"test-inspector/pkg/models"
+
+	"github.com/google/uuid"
+)
+
+// Strategy is a way to find the local result of the reference test, see Matcher.
+// @property Name - Returns the name of the strategy used in the --match option.
+// @property Keys - Returns keys of the test to index results by, tests without keys are not matched.
+// @property Match - Checks the local result with a common key and scores it, the best results win.
+type Strategy interface {
+	Name() string
+	Keys(r models.SupaResult) []string
+	Match(template, result models.SupaResult) (float64, bool)
+}
+
+// DefaultStrategies are names of strategies used if none are configured, from the most reliable one.
+var DefaultStrategies = []string{"id", "fullName", "name"}
+
+// strategies are all known strategies, see RegisterStrategy.
+var strategies = []Strategy{
+	idStrategy{},
+	fullNameStrategy{},
+	nameStrategy{},
+	fuzzyStrategy{Threshold: 0.8},
+}
+
+// RegisterStrategy adds the strategy or replaces the strategy with the same name.
+func RegisterStrategy(s Strategy) {
+	for i, known := range strategies {
+		if known.Name() == s.Name() {
+			strategies[i] = s
+			return
+		}
+	}
+	strategies = append(strategies, s)
+}
+
+// Strategies returns names of all known strategies.
+func Strategies() []string {
+	names := make([]string, 0, len(strategies))
+	for _, s := range strategies {
+		names = append(names, s.Name())
+	}
+	return names
+}
+
+// CompileStrategies returns strategies by their names in the same order.
+func CompileStrategies(names []string) ([]Strategy, error) {
+	res := []Strategy{}
+	seen := map[string]bool{}
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		s := findStrategy(name)
+		if s == nil {
+			return nil, fmt.Errorf("unsupported match strategy '%s', possible values: %s",
+				name, strings.Join(Strategies(), ", "))
+		}
+		res = append(res, s)
+	}
+	if len(res) == 0 {
+		return nil, fmt.Errorf("no match strategies, possible values: %s", strings.Join(Strategies(), ", "))
+	}
+	return res, nil
+}
+
+func findStrategy(name string) Strategy {
+	for _, s := range strategies {
+		if s.Name() == name {
+			return s
+		}
+	}
+	return nil
+}
+
+// Match is the local result of the reference test.
+// @property Result - The matched local result, nil if there is none or the match is ambiguous.
+// @property {string} Strategy - The name of the strategy that found the result, or the first strategy
+// that found candidates.
+// @property Candidates - Local results that match the reference test equally well, or the only result
+// that matches several reference tests equally well, by every strategy that found any.
+type Match struct {
+	Result     *models.SupaResult
+	Strategy   string
+	Candidates []models.SupaResult
+}
+
+// Ambiguous checks if the reference test has no single best local result.
+func (m Match) Ambiguous() bool {
+	return len(m.Candidates) > 0
+}
+
+// Matcher pairs reference tests with local results. Results are indexed by keys of every strategy once,
+// so every reference test is checked against a few results with the same key instead of all of them.
+// @property strategies - Strategies in the order they are tried.
+// @property results - Local results by their IDs.
+// @property index - IDs of local results by keys for every strategy, ordered by IDs.
+type Matcher struct {
+	strategies []Strategy
+	results    map[uuid.UUID]models.SupaResult
+	index      []map[string][]uuid.UUID
+}
+
+// NewMatcher indexes local results by keys of strategies.
+func NewMatcher(strategies []Strategy, results map[uuid.UUID]models.SupaResult) *Matcher {
+	m := &Matcher{strategies: strategies, results: results, index: make([]map[string][]uuid.UUID, len(strategies))}
+	ids := make([]uuid.UUID, 0, len(results))
+	for id := range results {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i].String() < ids[j].String() })
+	for i, s := range strategies {
+		m.index[i] = map[string][]uuid.UUID{}
+		for _, id := range ids {
+			for _, key := range uniqueKeys(s, results[id]) {
+				m.index[i][key] = append(m.index[i][key], id)
+			}
+		}
+	}
+	return m
+}
+
+// MatchAll returns matches of reference tests in the same order. Strategies are tried one by one
+// for all reference tests, so a result found by a more reliable strategy is not taken by a weaker one
+// for another test. Every local result is matched once. If several results match equally well,
+// or the same result is the best one of several reference tests, the next strategy is tried, and
+// the match is ambiguous only if no strategy finds a single result, so tests are never paired at random.
+func (m *Matcher) MatchAll(templates []models.SupaResult) []Match {
+	matches := make([]Match, len(templates))
+	done := make([]bool, len(templates))
+	claimed := map[uuid.UUID]bool{}
+	for i, s := range m.strategies {
+		best := make([][]uuid.UUID, len(templates))
+		wanted := map[uuid.UUID]int{}
+		for j, t := range templates {
+			if done[j] {
+				continue
+			}
+			best[j] = m.bestResults(i, t, claimed)
+			for _, id := range best[j] {
+				wanted[id]++
+			}
+		}
+		for j := range templates {
+			switch {
+			case len(best[j]) == 1 && wanted[best[j][0]] == 1:
+				r := m.results[best[j][0]]
+				matches[j] = Match{Result: &r, Strategy: s.Name()}
+				done[j] = true
+				claimed[r.ID] = true
+			case len(best[j]) > 0 && matches[j].Candidates == nil:
+				matches[j].Strategy = s.Name()
+				for _, id := range best[j] {
+					matches[j].Candidates = append(matches[j].Candidates, m.results[id])
+				}
+			}
+		}
+	}
+	// candidates taken by other reference tests are not ambiguous anymore
+	for j := range matches {
+		candidates := matches[j].Candidates
+		matches[j].Candidates = nil
+		for _, c := range candidates {
+			if !claimed[c.ID] {
+				matches[j].Candidates = append(matches[j].Candidates, c)
+			}
+		}
+	}
+	return matches
+}
+
+// bestResults returns unclaimed results with the best score of the strategy for the reference test.
+func (m *Matcher) bestResults(strategy int, t models.SupaResult, claimed map[uuid.UUID]bool) []uuid.UUID {
+	s := m.strategies[strategy]
+	best := []uuid.UUID{}
+	bestScore := 0.0
+	seen := map[uuid.UUID]bool{}
+	for _, key := range uniqueKeys(s, t) {
+		for _, id := range m.index[strategy][key] {
+			if claimed[id] || seen[id] {
+				continue
+			}
+			seen[id] = true
+			score, ok := s.Match(t, m.results[id])
+			switch {
+			case !ok || score < bestScore:
+			case score > bestScore || len(best) == 0:
+				best, bestScore = []uuid.UUID{id}, score
+			default:
+				best = append(best, id)
+			}
+		}
+	}
+	sort.Slice(best, func(i, j int) bool { return best[i].String() < best[j].String() })
+	return best
+}
+
+func uniqueKeys(s Strategy, r models.SupaResult) []string {
+	keys := []string{}
+	seen := map[string]bool{}
+	for _, key := range s.Keys(r) {
+		if key != "" && !seen[key] {
+			seen[key] = true
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// idStrategy matches tests by the explicit test ID: the testId label, supatms or tms link.
+// Labels of reference tests are stored with their results, see models.SupaResult.
+type idStrategy struct{}
+
+func (idStrategy) Name() string { return "id" }
+
+func (idStrategy) Keys(r models.SupaResult) []string {
+	a := models.AllureResult{Labels: r.Labels, Links: r.Links}
+	id, ok := a.FindID()
+	if !ok {
+		return nil
+	}
+	return []string{strconv.Itoa(int(id))}
+}
+
+func (idStrategy) Match(_, _ models.SupaResult) (float64, bool) { return 1, true }
+
+// fullNameStrategy matches tests by the full name, name and parameters, so parametrized tests are told apart.
+// The name is a part of the key, because some formats share the full name by all tests of a class,
+// for ex. the class name of junit tests.
+type fullNameStrategy struct{}
+
+func (fullNameStrategy) Name() string { return "fullName" }
+
+func (fullNameStrategy) Keys(r models.SupaResult) []string {
+	if r.FullName == "" {
+		return nil
+	}
+	params := make([]string, 0, len(r.Parameters))
+	for _, p := range r.Parameters {
+		params = append(params, p.Name+"="+p.Value)
+	}
+	sort.Strings(params)
+	return []string{r.FullName + "#" + r.Name + "(" + strings.Join(params, ", ") + ")"}
+}
+
+func (fullNameStrategy) Match(_, _ models.SupaResult) (float64, bool) { return 1, true }
+
+// nameStrategy matches tests by the normalized name if any of their suites match, see normalizeName.
+type nameStrategy struct{}
+
+func (nameStrategy) Name() string { return "name" }
+
+func (nameStrategy) Keys(r models.SupaResult) []string {
+	return []string{normalizeName(r.Name)}
+}
+
+func (nameStrategy) Match(template, result models.SupaResult) (float64, bool) {
+	return 1, checkSuiteNames(template, result)
+}
+
+// fuzzyStrategy matches tests of the same suites by the similarity of normalized names,
+// for ex. tests with typos. Results are indexed by suites, so names are compared within suites only.
+// @property {float64} Threshold - The minimal similarity from 0 to 1 of names of matched tests.
+type fuzzyStrategy struct {
+	Threshold float64
+}
+
+func (fuzzyStrategy) Name() string { return "fuzzy" }
+
+func (fuzzyStrategy) Keys(r models.SupaResult) []string {
+	return []string{normalizeName(r.ParentSuite), normalizeName(r.Suite), normalizeName(r.SubSuite)}
+}
+
+func (s fuzzyStrategy) Match(template, result models.SupaResult) (float64, bool) {
+	score := similarity(normalizeName(template.Name), normalizeName(result.Name))
+	return score, score >= s.Threshold && checkSuiteNames(template, result)
+}
+
+// similarity returns 1 for equal strings and 0 for completely different ones by the Levenshtein distance.
+func similarity(a, b string) float64 {
+	ra, rb := []rune(a), []rune(b)
+	longest := len(ra)
+	if len(rb) > longest {
+		longest = len(rb)
+	}
+	if longest == 0 {
+		return 1
+	}
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = prev[j-1] + cost
+			if prev[j]+1 < cur[j] {
+				cur[j] = prev[j] + 1
+			}
+			if cur[j-1]+1 < cur[j] {
+				cur[j] = cur[j-1] + 1
+			}
+		}
+		prev, cur = cur, prev
+	}
+	return 1 - float64(prev[len(rb)])/float64(longest)
+}
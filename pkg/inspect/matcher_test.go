@@ -0,0 +1,181 @@
+package inspect
+
+import (
+	"reflect"
+	"test-inspector/pkg/models"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func test(id byte, name, fullName, suite string) models.SupaResult {
+	return models.SupaResult{ID: uuid.UUID{id}, Name: name, FullName: fullName, Suite: suite, Status: "passed"}
+}
+
+func withLabel(r models.SupaResult, name, value string) models.SupaResult {
+	r.Labels = append(r.Labels, &models.Label{Name: name, Value: value})
+	return r
+}
+
+func withLink(r models.SupaResult, linkType, url string) models.SupaResult {
+	r.Links = append(r.Links, &models.Link{Name: url, URL: url, Type: &linkType})
+	return r
+}
+
+func resultsOf(tests ...models.SupaResult) map[uuid.UUID]models.SupaResult {
+	res := map[uuid.UUID]models.SupaResult{}
+	for _, r := range tests {
+		res[r.ID] = r
+	}
+	return res
+}
+
+// describe returns the ID and the strategy of the result, or IDs of candidates.
+func describe(m Match) []interface{} {
+	if m.Result != nil {
+		return []interface{}{m.Strategy, m.Result.ID[0]}
+	}
+	res := []interface{}{m.Strategy}
+	for _, c := range m.Candidates {
+		res = append(res, c.ID[0])
+	}
+	return res
+}
+
+func TestMatchAll(t *testing.T) {
+	tests := []struct {
+		name       string
+		strategies []string
+		templates  []models.SupaResult
+		results    []models.SupaResult
+		want       [][]interface{}
+	}{
+		{
+			name: "junit tests of one class",
+			templates: []models.SupaResult{
+				test(1, "testA", "com.example.Tests", "Tests"),
+				test(2, "testB", "com.example.Tests", "Tests"),
+				test(3, "testGone", "com.example.Tests", "Tests"),
+			},
+			results: []models.SupaResult{
+				test(11, "testB", "com.example.Tests", "Tests"),
+				test(12, "testA", "com.example.Tests", "Tests"),
+			},
+			want: [][]interface{}{{"fullName", byte(12)}, {"fullName", byte(11)}, {""}},
+		},
+		{
+			name:      "the id wins over the name",
+			templates: []models.SupaResult{withLink(test(1, "login", "", "auth"), "tms", "7")},
+			results: []models.SupaResult{
+				test(11, "login", "", "auth"),
+				withLink(test(12, "sign in", "", "auth"), "tms", "7"),
+			},
+			want: [][]interface{}{{"id", byte(12)}},
+		},
+		{
+			name:      "the testId label of the reference test",
+			templates: []models.SupaResult{withLabel(test(1, "login", "", "auth"), "testId", "42")},
+			results: []models.SupaResult{
+				test(11, "login", "", "auth"),
+				withLink(test(12, "sign in", "", "auth"), "supatms", "42"),
+			},
+			want: [][]interface{}{{"id", byte(12)}},
+		},
+		{
+			name:      "ties fall through to the next strategy",
+			templates: []models.SupaResult{test(1, "delete user", "users.delete", "api")},
+			results: []models.SupaResult{
+				test(11, "delete user", "users.delete", "ui"),
+				test(12, "delete user", "users.delete", "api"),
+			},
+			want: [][]interface{}{{"name", byte(12)}},
+		},
+		{
+			name:      "results found by a reliable strategy are not taken by a weaker one",
+			templates: []models.SupaResult{test(1, "delete user", "", "api"), test(2, "delete user", "users.delete", "api")},
+			results: []models.SupaResult{
+				test(11, "delete user", "", "api"),
+				test(12, "delete user", "users.delete", "api"),
+			},
+			want: [][]interface{}{{"name", byte(11)}, {"fullName", byte(12)}},
+		},
+		{
+			name:      "several results match equally well",
+			templates: []models.SupaResult{test(1, "delete user", "", "api")},
+			results:   []models.SupaResult{test(11, "delete user", "", "api"), test(12, "delete_user", "", "api")},
+			want:      [][]interface{}{{"name", byte(11), byte(12)}},
+		},
+		{
+			name:      "one result matches several reference tests",
+			templates: []models.SupaResult{test(1, "delete user", "", "api"), test(2, "deleteUser", "", "api")},
+			results:   []models.SupaResult{test(11, "delete user", "", "api")},
+			want:      [][]interface{}{{"name", byte(11)}, {"name", byte(11)}},
+		},
+		{
+			name:       "fuzzy names after exact ones",
+			strategies: []string{"name", "fuzzy"},
+			templates:  []models.SupaResult{test(1, "create user", "", "api"), test(2, "update user", "", "api")},
+			results:    []models.SupaResult{test(11, "create usr", "", "api"), test(12, "update user", "", "api")},
+			want:       [][]interface{}{{"fuzzy", byte(11)}, {"name", byte(12)}},
+		},
+		{
+			name:       "strategies in the configured order",
+			strategies: []string{"name", "id"},
+			templates:  []models.SupaResult{withLink(test(1, "login", "", "auth"), "tms", "7")},
+			results: []models.SupaResult{
+				test(11, "login", "", "auth"),
+				withLink(test(12, "sign in", "", "auth"), "tms", "7"),
+			},
+			want: [][]interface{}{{"name", byte(11)}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			names := tt.strategies
+			if names == nil {
+				names = DefaultStrategies
+			}
+			strategies, err := CompileStrategies(names)
+			if err != nil {
+				t.Fatal(err)
+			}
+			matches := NewMatcher(strategies, resultsOf(tt.results...)).MatchAll(tt.templates)
+			got := make([][]interface{}, 0, len(matches))
+			for _, m := range matches {
+				got = append(got, describe(m))
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("MatchAll() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompareUnmatchedReferences(t *testing.T) {
+	templates := []models.SupaResult{
+		test(1, "testA", "com.example.Tests", "Tests"),
+		test(2, "testB", "com.example.Tests", "Tests"),
+		test(3, "testGone", "com.example.Tests", "Tests"),
+		test(4, "delete user", "", "api"),
+	}
+	results := resultsOf(
+		test(11, "testA", "com.example.Tests", "Tests"),
+		test(12, "testB", "com.example.Tests", "Tests"),
+		test(13, "delete user", "", "api"),
+		test(14, "delete_user", "", "api"),
+	)
+	r := Compare(templates, results, Options{})
+	kinds := []Kind{}
+	for _, f := range r.Findings {
+		if f.Severity != Error {
+			t.Errorf("%s finding is a %s, want an error", f.Kind, f.Severity)
+		}
+		kinds = append(kinds, f.Kind)
+	}
+	if want := []Kind{MissingTest, AmbiguousMatch}; !reflect.DeepEqual(kinds, want) {
+		t.Errorf("Compare() findings = %v, want %v", kinds, want)
+	}
+	if r.Errors != 2 {
+		t.Errorf("Compare() errors = %d, want 2", r.Errors)
+	}
+}
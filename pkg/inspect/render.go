@@ -0,0 +1,141 @@
+package inspect
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"test-inspector/pkg/color"
+)
+
+// Console is the format of the report printed to the terminal.
+const Console = "console"
+
+// Renderer writes the report in a single format.
+type Renderer func(w io.Writer, r *Report) error
+
+var renderers = map[string]Renderer{
+	Console:    RenderConsole,
+	"json":     RenderJSON,
+	"junit":    RenderJUnit,
+	"markdown": RenderMarkdown,
+}
+
+// Formats returns names of all formats of the report.
+func Formats() []string {
+	names := make([]string, 0, len(renderers))
+	for name := range renderers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Get returns the renderer of the format.
+func Get(format string) (Renderer, error) {
+	render, ok := renderers[format]
+	if !ok {
+		return nil, fmt.Errorf("unsupported report format '%s', possible values: %s",
+			format, strings.Join(Formats(), ", "))
+	}
+	return render, nil
+}
+
+// Render writes the report in the format.
+func Render(w io.Writer, format string, r *Report) error {
+	render, err := Get(format)
+	if err != nil {
+		return err
+	}
+	return render(w, r)
+}
+
+// RenderConsole writes the colored report for the terminal.
+func RenderConsole(w io.Writer, r *Report) error {
+	var b strings.Builder
+	b.WriteString(color.Blue + "Test Results comparison report:\n" + color.Reset)
+
+	fmt.Fprintf(&b, "\n%s%d%s test results found in local run (%s)\n",
+		color.Blue, r.LocalCount, color.Reset, strings.Join(r.LocalPaths, ", "))
+	fmt.Fprintf(&b, "%s%d%s test results found in template run\n",
+		color.Green, r.ReferenceCount, color.Reset)
+	if r.LocalCount < r.ReferenceCount {
+		fmt.Fprintf(&b, "\n%sWARNING%s: number of test results in local run (%d) is less "+
+			"then number of test results in template run (%d)\n",
+			color.Yellow, color.Reset, r.LocalCount, r.ReferenceCount)
+	} else if r.LocalCount > r.ReferenceCount {
+		fmt.Fprintf(&b, "\n%sWARNING%s: number of test results in local run (%d) differs "+
+			"from number of test results in template run (%d)\n",
+			color.Yellow, color.Reset, r.LocalCount, r.ReferenceCount)
+	}
+	b.WriteString("\n")
+
+	for _, note := range r.Notes {
+		b.WriteString(note + "\n")
+	}
+	for _, f := range r.Findings {
+		if f.Kind == ExtraTest {
+			continue
+		}
+		b.WriteString(consoleLine(f, f.Message))
+		for _, c := range f.Diff {
+			b.WriteString("\t" + colorChange(c) + "\n")
+		}
+		for _, c := range f.Candidates {
+			b.WriteString("\tcandidate: " + candidateName(c) + "\n")
+		}
+	}
+	groups, extras := r.Extras()
+	if len(groups) > 0 {
+		b.WriteString("\nTest results not found in template run:\n")
+	}
+	for _, group := range groups {
+		fmt.Fprintf(&b, "%s%s%s (%d)\n", color.Blue, group, color.Reset, len(extras[group]))
+		for _, f := range extras[group] {
+			b.WriteString("\t" + consoleLine(f, f.Local.Name))
+		}
+	}
+
+	fmt.Fprintf(&b, "\n%s%d errors%s and %s%d warnings%s found\n",
+		color.Red, r.Errors, color.Reset,
+		color.Yellow, r.Warnings, color.Reset)
+	if r.Passed() {
+		b.WriteString(color.Green + "All checks passed!\n" + color.Reset)
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func consoleLine(f Finding, text string) string {
+	if f.Severity == Error {
+		return fmt.Sprintf("%s[ERROR]%s: %s\n", color.Red, color.Reset, text)
+	}
+	return fmt.Sprintf("%s[WARN]%s: %s\n", color.Yellow, color.Reset, text)
+}
+
+// colorChange colors the line of the step diff like diff tools do.
+func colorChange(c StepChange) string {
+	switch c.Change {
+	case Removed:
+		return color.Red + c.String() + color.Reset
+	case Inserted:
+		return color.Green + c.String() + color.Reset
+	case Renamed, Moved:
+		return color.Yellow + c.String() + color.Reset
+	default:
+		return c.String()
+	}
+}
+
+// RenderJSON writes the report as indented json.
+func RenderJSON(w io.Writer, r *Report) error {
+	report := *r
+	if report.Findings == nil {
+		// an empty list is easier to consume than null
+		report.Findings = []Finding{}
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(&report)
+}
@@ -0,0 +1,120 @@
+package inspect
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"test-inspector/pkg/models"
+)
+
+// Ignore is the severity of status pairs that are not reported.
+const Ignore Severity = "ignore"
+
+// anyStatus matches any status in status pairs.
+const anyStatus = "*"
+
+// defaultStatuses are severities of status pairs reported by default, other pairs are ignored.
+// Tests that pass in the reference run are expected to pass locally too.
+var defaultStatuses = map[string]Severity{
+	"passed/failed":  Error,
+	"passed/broken":  Error,
+	"passed/error":   Error, // junit status of tests with errors in launches uploaded as is
+	"passed/skipped": Warning,
+	"passed/pending": Warning,
+	"passed/unknown": Warning,
+}
+
+// StatusConfig is the configuration of status checks in the config file, it maps status pairs
+// `reference/local`, for ex. `passed/skipped`, to severities: error, warning or ignore.
+// `*` matches any status, for ex. `*/skipped`. Pairs are added to the default ones.
+type StatusConfig map[string]string
+
+// StatusRules are compiled severities of status pairs.
+type StatusRules struct {
+	severities map[string]Severity
+}
+
+// CompileStatuses checks the config and adds its pairs to the default ones.
+func CompileStatuses(cfg StatusConfig) (*StatusRules, error) {
+	rules := &StatusRules{severities: map[string]Severity{}}
+	for pair, severity := range defaultStatuses {
+		rules.severities[pair] = severity
+	}
+	pairs := make([]string, 0, len(cfg))
+	for pair := range cfg {
+		pairs = append(pairs, pair)
+	}
+	sort.Strings(pairs)
+	for _, pair := range pairs {
+		reference, local, ok := strings.Cut(strings.ToLower(pair), "/")
+		if !ok || reference == "" || local == "" {
+			return nil, fmt.Errorf("bad status pair %s, expected reference/local, for ex. passed/failed", pair)
+		}
+		severity := Severity(strings.ToLower(cfg[pair]))
+		if severity != Error && severity != Warning && severity != Ignore {
+			return nil, fmt.Errorf("bad severity %s of status pair %s, possible values: %s, %s, %s",
+				cfg[pair], pair, Error, Warning, Ignore)
+		}
+		rules.severities[reference+"/"+local] = severity
+	}
+	return rules, nil
+}
+
+// Severity returns the severity of the pair of statuses, the most specific pair wins:
+// `passed/failed`, then `passed/*`, `*/failed` and `*/*`. Same statuses are never reported.
+func (r *StatusRules) Severity(reference, local string) Severity {
+	if reference == local {
+		return Ignore
+	}
+	severities := defaultStatuses
+	if r != nil {
+		severities = r.severities
+	}
+	for _, pair := range []string{
+		reference + "/" + local,
+		reference + "/" + anyStatus,
+		anyStatus + "/" + local,
+		anyStatus + "/" + anyStatus,
+	} {
+		if severity, ok := severities[pair]; ok {
+			return severity
+		}
+	}
+	return Ignore
+}
+
+// compareStatus returns the finding if the status of the local test is a regression from the reference.
+func compareStatus(t, r models.SupaResult, rules *StatusRules) *Finding {
+	severity := rules.Severity(t.Status, r.Status)
+	if severity == Ignore {
+		return nil
+	}
+	return &Finding{
+		Kind:     StatusRegression,
+		Severity: severity,
+		Template: identityOf(t),
+		Local:    identityOf(r),
+		Expected: t.Status,
+		Actual:   r.Status,
+		Message: fmt.Sprintf("%s in reference, %s locally: %s",
+			describeStatus(t.Status), describeStatus(r.Status), testName(identityOf(t))),
+	}
+}
+
+// describeStatus returns the status as a verb, so findings read as `passes in reference, fails locally`.
+func describeStatus(status string) string {
+	switch status {
+	case "passed":
+		return "passes"
+	case "failed":
+		return "fails"
+	case "broken", "error":
+		return "is broken"
+	case "skipped":
+		return "skipped"
+	case "pending":
+		return "pending"
+	default:
+		return "has status " + status
+	}
+}
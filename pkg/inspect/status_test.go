@@ -0,0 +1,52 @@
+package inspect
+
+import "testing"
+
+func TestStatusRulesSeverity(t *testing.T) {
+	rules, err := CompileStatuses(StatusConfig{
+		"passed/skipped": "error",
+		"failed/*":       "warning",
+		"*/unknown":      "ignore",
+		"*/*":            "warning",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		rules            *StatusRules
+		reference, local string
+		want             Severity
+	}{
+		{nil, "passed", "failed", Error},
+		{nil, "passed", "broken", Error},
+		{nil, "passed", "error", Error},
+		{nil, "passed", "skipped", Warning},
+		{nil, "passed", "unknown", Warning},
+		{nil, "failed", "passed", Ignore},
+		{nil, "passed", "passed", Ignore},
+		// configured pairs override default ones
+		{rules, "passed", "skipped", Error},
+		// the exact default pair wins over less specific configured ones
+		{rules, "passed", "unknown", Warning},
+		{rules, "passed", "failed", Error},
+		// reference/* wins over */local
+		{rules, "failed", "unknown", Warning},
+		{rules, "broken", "unknown", Ignore},
+		{rules, "skipped", "passed", Warning},
+		{rules, "broken", "broken", Ignore},
+	}
+	for _, tt := range tests {
+		if got := tt.rules.Severity(tt.reference, tt.local); got != tt.want {
+			t.Errorf("Severity(%s, %s) = %s, want %s (custom rules: %v)",
+				tt.reference, tt.local, got, tt.want, tt.rules != nil)
+		}
+	}
+}
+
+func TestCompileStatusesErrors(t *testing.T) {
+	for _, cfg := range []StatusConfig{{"passed": "error"}, {"passed/failed": "fatal"}, {"/failed": "error"}} {
+		if _, err := CompileStatuses(cfg); err == nil {
+			t.Errorf("CompileStatuses(%v) succeeded, want error", cfg)
+		}
+	}
+}
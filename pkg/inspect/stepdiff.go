@@ -0,0 +1,279 @@
+package inspect
+
+import (
+	"fmt"
+	"strings"
+	"test-inspector/pkg/models"
+)
+
+// Change is the kind of the difference of the step between the reference test and the local test.
+type Change string
+
+const (
+	// Same is a step that is the same in both tests, it is kept in the diff as the context.
+	Same Change = "same"
+	// Removed is a step of the reference test missing in the local test.
+	Removed Change = "removed"
+	// Inserted is a step of the local test missing in the reference test.
+	Inserted Change = "inserted"
+	// Renamed is a step with another name in the same place of the local test.
+	Renamed Change = "renamed"
+	// Moved is a step in another place among its siblings in the local test.
+	Moved Change = "moved"
+)
+
+// changePrefixes are prefixes of lines of the unified diff view.
+var changePrefixes = map[Change]string{
+	Same:     " ",
+	Removed:  "-",
+	Inserted: "+",
+	Renamed:  "~",
+	Moved:    ">",
+}
+
+// StepChange is a single line of the diff of step trees.
+// @property {Change} Change - The kind of the difference.
+// @property {[]string} Path - Names of parent steps in the reference test, empty for top-level steps.
+// @property {string} Name - The name of the step in the reference test, the local name for inserted steps.
+// @property {string} LocalName - The name of the step in the local test if it was renamed.
+// @property TemplatePosition - The position of the step among its siblings in the reference test
+// starting from 0, nil for inserted steps.
+// @property LocalPosition - The position of the step among its siblings in the local test,
+// nil for removed steps.
+type StepChange struct {
+	Change           Change   `json:"change"`
+	Path             []string `json:"path"`
+	Name             string   `json:"name"`
+	LocalName        string   `json:"localName,omitempty"`
+	TemplatePosition *int     `json:"templatePosition,omitempty"`
+	LocalPosition    *int     `json:"localPosition,omitempty"`
+}
+
+// String formats the change as a line of the unified diff view indented by the depth of the step.
+func (c StepChange) String() string {
+	line := changePrefixes[c.Change] + " " + strings.Repeat("  ", len(c.Path))
+	switch c.Change {
+	case Renamed:
+		return line + c.Name + " -> " + c.LocalName
+	case Moved:
+		return line + fmt.Sprintf("%s (moved from %d to %d)", c.Name, *c.TemplatePosition, *c.LocalPosition)
+	default:
+		return line + c.Name
+	}
+}
+
+// StepDiff is the diff of step trees of the reference test and the local test.
+type StepDiff []StepChange
+
+// Changed checks if the local test has any difference in steps.
+func (d StepDiff) Changed() bool {
+	return d.Count(Removed)+d.Count(Inserted)+d.Count(Renamed)+d.Count(Moved) > 0
+}
+
+// Count returns the number of changes of the kind.
+func (d StepDiff) Count(change Change) int {
+	n := 0
+	for _, c := range d {
+		if c.Change == change {
+			n++
+		}
+	}
+	return n
+}
+
+// Summary counts changes, for ex. `1 removed, 2 inserted`.
+func (d StepDiff) Summary() string {
+	counts := []string{}
+	for _, change := range []Change{Removed, Inserted, Renamed, Moved} {
+		if n := d.Count(change); n > 0 {
+			counts = append(counts, fmt.Sprintf("%d %s", n, change))
+		}
+	}
+	return strings.Join(counts, ", ")
+}
+
+// Lines returns the unified diff view of the step trees.
+func (d StepDiff) Lines() []string {
+	lines := make([]string, 0, len(d))
+	for _, c := range d {
+		lines = append(lines, c.String())
+	}
+	return lines
+}
+
+// diffSteps compares step trees of the reference test and the local test. Siblings are aligned
+// by the longest common subsequence of names, so every difference is reported and not only the first one.
+// Steps missing on one side with the same name on the other are moved, other steps between
+// the same aligned steps are renamed in order, the rest are removed or inserted.
+// Children of aligned, moved and renamed steps are compared the same way.
+func diffSteps(templateSteps, resultSteps []*models.StepContainer, path []string) StepDiff {
+	pairs := alignSteps(templateSteps, resultSteps)
+	diff := StepDiff{}
+	for _, p := range pairs {
+		change := StepChange{Change: p.change, Path: path}
+		if p.template >= 0 {
+			change.Name = templateSteps[p.template].Name
+			change.TemplatePosition = intRef(p.template)
+		}
+		if p.result >= 0 {
+			if p.template < 0 {
+				change.Name = resultSteps[p.result].Name
+			} else if p.change == Renamed {
+				change.LocalName = resultSteps[p.result].Name
+			}
+			change.LocalPosition = intRef(p.result)
+		}
+		diff = append(diff, change)
+
+		switch {
+		case p.template >= 0 && p.result >= 0:
+			diff = append(diff, diffSteps(templateSteps[p.template].StepContainer,
+				resultSteps[p.result].StepContainer, childPath(path, change.Name))...)
+		case p.template >= 0:
+			diff = append(diff, allSteps(Removed, templateSteps[p.template].StepContainer,
+				childPath(path, change.Name), true)...)
+		default:
+			diff = append(diff, allSteps(Inserted, resultSteps[p.result].StepContainer,
+				childPath(path, change.Name), false)...)
+		}
+	}
+	return diff
+}
+
+// pair is an aligned pair of steps by their indexes, -1 if the step is missing on that side.
+type pair struct {
+	change           Change
+	template, result int
+}
+
+// alignSteps pairs siblings and returns pairs in the order of the unified view:
+// in every gap between steps of the common subsequence removed steps go first,
+// then other steps in the order of the local test.
+func alignSteps(templateSteps, resultSteps []*models.StepContainer) []pair {
+	templateKeys := make([]string, len(templateSteps))
+	for i, s := range templateSteps {
+		templateKeys[i] = replaceAllSubstringsInBrackets(s.Name)
+	}
+	resultKeys := make([]string, len(resultSteps))
+	for i, s := range resultSteps {
+		resultKeys[i] = replaceAllSubstringsInBrackets(s.Name)
+	}
+	common := lcs(templateKeys, resultKeys)
+
+	// steps outside of the common subsequence with the same name on the other side were moved
+	movedFrom := map[int]int{}
+	movedTo := map[int]int{}
+	inCommonT, inCommonR := map[int]bool{}, map[int]bool{}
+	for _, c := range common {
+		inCommonT[c[0]], inCommonR[c[1]] = true, true
+	}
+	for i, key := range templateKeys {
+		if inCommonT[i] {
+			continue
+		}
+		for j, other := range resultKeys {
+			if _, ok := movedTo[j]; !ok && !inCommonR[j] && key == other {
+				movedFrom[i], movedTo[j] = j, i
+				break
+			}
+		}
+	}
+
+	pairs := []pair{}
+	i, j := 0, 0
+	for _, anchor := range append(common, [2]int{len(templateKeys), len(resultKeys)}) {
+		removed := []int{}
+		for ; i < anchor[0]; i++ {
+			if _, ok := movedFrom[i]; !ok {
+				removed = append(removed, i)
+			}
+		}
+		inserted := []int{}
+		for k := j; k < anchor[1]; k++ {
+			if _, ok := movedTo[k]; !ok {
+				inserted = append(inserted, k)
+			}
+		}
+		// steps in the same gap on both sides are renamed in order
+		renamed := map[int]int{}
+		for k, t := range removed {
+			if k < len(inserted) {
+				renamed[inserted[k]] = t
+			} else {
+				pairs = append(pairs, pair{change: Removed, template: t, result: -1})
+			}
+		}
+		for k := j; k < anchor[1]; k++ {
+			if t, ok := movedTo[k]; ok {
+				pairs = append(pairs, pair{change: Moved, template: t, result: k})
+			} else if t, ok := renamed[k]; ok {
+				pairs = append(pairs, pair{change: Renamed, template: t, result: k})
+			} else {
+				pairs = append(pairs, pair{change: Inserted, template: -1, result: k})
+			}
+		}
+		j = anchor[1]
+		if anchor[0] < len(templateKeys) {
+			pairs = append(pairs, pair{change: Same, template: anchor[0], result: anchor[1]})
+			i, j = anchor[0]+1, anchor[1]+1
+		}
+	}
+	return pairs
+}
+
+// lcs returns index pairs of the longest common subsequence of a and b.
+func lcs(a, b []string) [][2]int {
+	lengths := make([][]int, len(a)+1)
+	for i := range lengths {
+		lengths[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lengths[i][j] = lengths[i+1][j+1] + 1
+			} else if lengths[i+1][j] >= lengths[i][j+1] {
+				lengths[i][j] = lengths[i+1][j]
+			} else {
+				lengths[i][j] = lengths[i][j+1]
+			}
+		}
+	}
+	res := [][2]int{}
+	for i, j := 0, 0; i < len(a) && j < len(b); {
+		switch {
+		case a[i] == b[j]:
+			res = append(res, [2]int{i, j})
+			i++
+			j++
+		case lengths[i+1][j] >= lengths[i][j+1]:
+			i++
+		default:
+			j++
+		}
+	}
+	return res
+}
+
+// allSteps returns the whole subtree of the removed or inserted step as changes of the same kind.
+func allSteps(change Change, steps []*models.StepContainer, path []string, template bool) StepDiff {
+	diff := StepDiff{}
+	for i, s := range steps {
+		c := StepChange{Change: change, Path: path, Name: s.Name}
+		if template {
+			c.TemplatePosition = intRef(i)
+		} else {
+			c.LocalPosition = intRef(i)
+		}
+		diff = append(diff, c)
+		diff = append(diff, allSteps(change, s.StepContainer, childPath(path, s.Name), template)...)
+	}
+	return diff
+}
+
+func childPath(path []string, name string) []string {
+	return append(path[:len(path):len(path)], name)
+}
+
+func intRef(i int) *int {
+	return &i
+}
@@ -0,0 +1,111 @@
+package inspect
+
+import (
+	"reflect"
+	"test-inspector/pkg/models"
+	"testing"
+)
+
+func step(name string, children ...*models.StepContainer) *models.StepContainer {
+	return &models.StepContainer{Name: name, Status: "passed", StepContainer: children}
+}
+
+func steps(s ...*models.StepContainer) []*models.StepContainer {
+	return s
+}
+
+func TestDiffSteps(t *testing.T) {
+	tests := []struct {
+		name        string
+		template    []*models.StepContainer
+		local       []*models.StepContainer
+		wantLines   []string
+		wantSummary string
+	}{
+		{
+			name:        "same steps",
+			template:    steps(step("A", step("A1")), step("B")),
+			local:       steps(step("A", step("A1")), step("B")),
+			wantLines:   []string{"  A", "    A1", "  B"},
+			wantSummary: "",
+		},
+		{
+			name:        "reorder",
+			template:    steps(step("A"), step("B"), step("C")),
+			local:       steps(step("A"), step("C"), step("B")),
+			wantLines:   []string{"  A", "  C", "> B (moved from 1 to 2)"},
+			wantSummary: "1 moved",
+		},
+		{
+			name:        "rename in the gap",
+			template:    steps(step("A"), step("B"), step("C")),
+			local:       steps(step("A"), step("X"), step("C")),
+			wantLines:   []string{"  A", "~ B -> X", "  C"},
+			wantSummary: "1 renamed",
+		},
+		{
+			name:        "rename and insert in the gap of nested steps",
+			template:    steps(step("A", step("A1"))),
+			local:       steps(step("A", step("A2"), step("A3"))),
+			wantLines:   []string{"  A", "~   A1 -> A2", "+   A3"},
+			wantSummary: "1 inserted, 1 renamed",
+		},
+		{
+			name:        "removed subtree",
+			template:    steps(step("A"), step("B", step("B1"), step("B2")), step("C")),
+			local:       steps(step("A"), step("C")),
+			wantLines:   []string{"  A", "- B", "-   B1", "-   B2", "  C"},
+			wantSummary: "3 removed",
+		},
+		{
+			name:        "inserted subtree",
+			template:    steps(step("A")),
+			local:       steps(step("A"), step("B", step("B1"))),
+			wantLines:   []string{"  A", "+ B", "+   B1"},
+			wantSummary: "2 inserted",
+		},
+		{
+			name:        "values in brackets are ignored",
+			template:    steps(step("open {id=1}", step("type {text=a}"))),
+			local:       steps(step("open {id=2}", step("type {text=b}"))),
+			wantLines:   []string{"  open {id=1}", "    type {text=a}"},
+			wantSummary: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diff := diffSteps(tt.template, tt.local, []string{})
+			if got := diff.Lines(); !reflect.DeepEqual(got, tt.wantLines) {
+				t.Errorf("Lines() = %q, want %q", got, tt.wantLines)
+			}
+			if got := diff.Summary(); got != tt.wantSummary {
+				t.Errorf("Summary() = %q, want %q", got, tt.wantSummary)
+			}
+			if diff.Changed() != (tt.wantSummary != "") {
+				t.Errorf("Changed() = %v, want %v", diff.Changed(), tt.wantSummary != "")
+			}
+		})
+	}
+}
+
+func TestDiffStepsPositions(t *testing.T) {
+	diff := diffSteps(steps(step("A"), step("B"), step("C")), steps(step("C"), step("A"), step("D")), []string{})
+	// the common subsequence is `C`, so `A` moved and `B`, `D` are in different gaps
+	want := StepDiff{
+		{Change: Removed, Path: []string{}, Name: "B", TemplatePosition: intRef(1)},
+		{Change: Same, Path: []string{}, Name: "C", TemplatePosition: intRef(2), LocalPosition: intRef(0)},
+		{Change: Moved, Path: []string{}, Name: "A", TemplatePosition: intRef(0), LocalPosition: intRef(1)},
+		{Change: Inserted, Path: []string{}, Name: "D", LocalPosition: intRef(2)},
+	}
+	if !reflect.DeepEqual(diff, want) {
+		t.Errorf("diffSteps() = %v, want %v", diff.Lines(), want.Lines())
+	}
+}
+
+func TestLCS(t *testing.T) {
+	got := lcs([]string{"a", "b", "c", "d"}, []string{"b", "x", "d"})
+	want := [][2]int{{1, 0}, {3, 2}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("lcs() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,128 @@
+package jest
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"test-inspector/pkg/diag"
+	"test-inspector/pkg/files"
+	"test-inspector/pkg/models"
+	"test-inspector/pkg/supatms"
+
+	"github.com/google/uuid"
+)
+
+// Parser is a report parser for `jest --json` output.
+type Parser struct{}
+
+// Name returns the report type of jest json reports.
+func (Parser) Name() string {
+	return "jest"
+}
+
+// Detect checks if the path is a jest json file or a folder containing one.
+func (Parser) Detect(resultsPath string) bool {
+	reports, err := files.List(resultsPath, ".json")
+	if err != nil {
+		return false
+	}
+	for _, f := range reports {
+		if _, err := parseFile(f); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// ReadResults reads jest json report, see ReadResults.
+func (Parser) ReadResults(resultsPath string) (map[uuid.UUID]models.SupaResult, error) {
+	return ReadResults(resultsPath)
+}
+
+// ReadResults reads jest json report file or all such files in the folder
+// and returns them as a map of SupaResults
+func ReadResults(resultsPath string) (map[uuid.UUID]models.SupaResult, error) {
+	reports, err := files.List(resultsPath, ".json")
+	if err != nil {
+		return nil, err
+	}
+
+	results := map[uuid.UUID]models.SupaResult{}
+	diagnostics := &diag.Collector{}
+	for _, f := range reports {
+		report, err := parseFile(f)
+		if err != nil {
+			// other json files are skipped silently, only broken ones are reported
+			if diag.Malformed(err) {
+				diagnostics.Add(diag.Skip(f, err))
+			}
+			continue
+		}
+		for _, file := range report.TestResults {
+			for _, test := range file.AssertionResults {
+				res := supatms.ToResult(0, *convertTest(file, test), "")
+				results[res.ID] = res
+			}
+		}
+	}
+	return results, diagnostics.Err()
+}
+
+// parseFile reads the file and returns an error if it is not a jest report.
+func parseFile(path string) (*models.JestReport, error) {
+	jsonFile, err := files.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var report models.JestReport
+	if err = json.Unmarshal(jsonFile, &report); err != nil {
+		return nil, err
+	}
+	if report.NumTotalTests == nil || report.TestResults == nil {
+		return nil, fmt.Errorf("%s is not a jest report", path)
+	}
+	return &report, nil
+}
+
+func convertTest(file *models.JestTestFile, test *models.JestAssertionResult) *models.AllureResult {
+	suites := test.AncestorTitles
+	if len(suites) == 0 {
+		suites = []string{strings.TrimSuffix(filepath.Base(file.Name), filepath.Ext(file.Name))}
+	}
+	fullName := test.FullName
+	ar := &models.AllureResult{
+		Name:     test.Title,
+		Status:   convertStatus(test.Status),
+		UUID:     uuid.New(),
+		FullName: &fullName,
+		Labels: append(supatms.SuiteLabels(suites),
+			&models.Label{Name: "package", Value: file.Name},
+			&models.Label{Name: "framework", Value: "jest"},
+			&models.Label{Name: "language", Value: "javascript"},
+		),
+	}
+	if test.Duration != nil {
+		ar.Stop = *test.Duration
+	}
+	if len(test.FailureMessages) > 0 {
+		message := strings.SplitN(test.FailureMessages[0], "\n", 2)[0]
+		trace := strings.Join(test.FailureMessages, "\n")
+		ar.StatusDetails = &models.StatusDetails{
+			Message: &message,
+			Trace:   &trace,
+		}
+	}
+	return ar
+}
+
+func convertStatus(status string) string {
+	switch status {
+	case "passed", "failed", "skipped":
+		return status
+	case "pending", "todo", "disabled":
+		return "skipped"
+	default:
+		return "unknown"
+	}
+}
@@ -0,0 +1,41 @@
+package jest
+
+import (
+	"test-inspector/internal/parsertest"
+	"test-inspector/pkg/diag"
+	"testing"
+)
+
+func TestReadResults(t *testing.T) {
+	tests := []struct {
+		name string
+		want parsertest.Want
+	}{
+		{"rejects anonymous users", parsertest.Want{
+			FullName: "Auth rejects anonymous users", Suite: "Auth", Status: "passed"}},
+		{"works with email", parsertest.Want{
+			FullName: "Auth sign in works with email", ParentSuite: "Auth", Suite: "sign in",
+			Status: "failed", Message: "expect(received).toBe(expected)"}},
+		{"redirects", parsertest.Want{ParentSuite: "Auth", Suite: "sign in", SubSuite: "with sso", Status: "skipped"}},
+		{"remembers the provider", parsertest.Want{SubSuite: "with sso", Status: "skipped"}},
+		// tests outside of describe blocks are grouped by the file
+		{"responds", parsertest.Want{FullName: "responds", Suite: "health.test", Status: "passed"}},
+	}
+	results, d := parsertest.Read(t, ReadResults, "testdata")
+	parsertest.Malformed(t, d, "broken.json", diag.Syntax)
+	if len(results) != len(tests) {
+		t.Fatalf("results %v, want %d", results.Names(), len(tests))
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := results.Find(t, tt.name)
+			parsertest.Check(t, r, tt.want)
+			if pkg := parsertest.Label(r, "package"); len(pkg) != 1 || pkg[0] == "" {
+				t.Errorf("package labels %v, want the test file", pkg)
+			}
+		})
+	}
+	if r := results.Find(t, "works with email"); r.Stop != 12 {
+		t.Errorf("stop %d, want the duration 12", r.Stop)
+	}
+}
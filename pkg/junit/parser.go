@@ -1,7 +1,12 @@
 package junit
 
 import (
+	"fmt"
 	"strings"
+	"test-inspector/pkg/diag"
+	"test-inspector/pkg/files"
+	"test-inspector/pkg/labelrules"
+	"test-inspector/pkg/markers"
 	"test-inspector/pkg/models"
 	"test-inspector/pkg/supatms"
 
@@ -9,53 +14,130 @@ import (
 	"github.com/joshdk/go-junit"
 )
 
-// ReadResults reads the results from the junit report and returns them as a map of SupaResults
+var (
+	// defaultSteps are step markers used when the parser is not configured.
+	defaultSteps = markers.MustCompile(markers.Config{})
+	// defaultLabels are label rules used when the parser is not configured.
+	defaultLabels = labelrules.MustCompile(labelrules.Config{})
+)
+
+// Parser is a report parser for junit xml reports.
+// @property Steps - Step markers to build steps from the output of tests, the default preset if nil.
+// @property Labels - Rules to derive suite labels of tests, the default preset if nil.
+type Parser struct {
+	Steps  *markers.Rules
+	Labels *labelrules.Rules
+}
+
+// Name returns the report type of junit reports.
+func (Parser) Name() string {
+	return "junit"
+}
+
+// Detect checks if the path is a junit xml file or a folder containing one.
+func (Parser) Detect(resultsPath string) bool {
+	return files.HasXMLRoot(resultsPath, "testsuites", "testsuite")
+}
+
+// ReadResults reads junit xml report with the configured step markers and label rules, see ReadResults.
+func (p Parser) ReadResults(resultsPath string) (map[uuid.UUID]models.SupaResult, error) {
+	if p.Steps == nil {
+		p.Steps = defaultSteps
+	}
+	if p.Labels == nil {
+		p.Labels = defaultLabels
+	}
+	return p.readResults(resultsPath)
+}
+
+// testResult is a junit test converted to allure result with the number of its runs.
+type testResult struct {
+	models.AllureResult
+	attempts int
+	file     string
+}
+
+// ReadResults reads the results from the junit report and returns them as a map of SupaResults.
+// Steps and suite labels are built with the default step markers and label rules.
 func ReadResults(resultsPath string) (map[uuid.UUID]models.SupaResult, error) {
-	results, err := parseResults(resultsPath)
+	return Parser{}.ReadResults(resultsPath)
+}
+
+func (p Parser) readResults(resultsPath string) (map[uuid.UUID]models.SupaResult, error) {
+	diagnostics := &diag.Collector{}
+	results, err := p.parseResults(resultsPath, diagnostics)
 	if err != nil {
 		return nil, err
 	}
 
 	suparesults := map[uuid.UUID]models.SupaResult{}
 	for _, r := range results {
-		supares := supatms.ToResult(0, r, r.Steps[0].Name)
+		r := r
+		stepsRaw, err := supatms.StepsJSON(&r.AllureResult)
+		if err != nil {
+			diagnostics.Add(diag.Warn(r.file, diag.Unsupported,
+				fmt.Sprintf("steps of %s could not be read: %v", r.Name, err)))
+		}
+		supares := supatms.ToResult(0, r.AllureResult, stepsRaw)
+		supares.Attempts = int16(r.attempts)
 		suparesults[supares.ID] = supares
 	}
 
-	return suparesults, nil
+	return suparesults, diagnostics.Err()
 }
 
-func parseResults(resultsPath string) ([]models.AllureResult, error) {
-	var suites []junit.Suite
-	var err error
-	if strings.Contains(resultsPath, ".xml") {
-		suites, err = junit.IngestFile(resultsPath)
-	} else {
-		suites, err = junit.IngestDir(resultsPath)
-	}
+// parseResults reads all junit files, malformed files are skipped and added to diagnostics.
+func (p Parser) parseResults(resultsPath string, diagnostics *diag.Collector) ([]testResult, error) {
+	reports, err := files.List(resultsPath, ".xml")
 	if err != nil {
 		return nil, err
 	}
-	res := []models.AllureResult{}
-
-	for _, suite := range suites {
-		res = append(res, convertTests(suite, suite.Package)...)
+	res := []testResult{}
+	for _, f := range reports {
+		data, err := files.ReadFile(f)
+		if err != nil {
+			diagnostics.Add(diag.Skip(f, err))
+			continue
+		}
+		suites, err := junit.Ingest(data)
+		if err != nil {
+			diagnostics.Add(diag.Skip(f, err))
+			continue
+		}
+		// go-junit keeps suites in the document order, so extensions are matched by position
+		extensions := parseSurefire(data)
+		for i, suite := range suites {
+			for _, r := range p.convertTests(suite, suite.Package, matchSuite(extensions, i, suite)) {
+				r.file = f
+				res = append(res, r)
+			}
+		}
 	}
 	return res, nil
 }
 
-func convertTests(suite junit.Suite, parentSuite string) []models.AllureResult {
-	res := []models.AllureResult{}
-	for _, test := range suite.Tests {
+func (p Parser) convertTests(suite junit.Suite, parentSuite string, ext *models.JunitTestSuite) []testResult {
+	res := []testResult{}
+	// tests of the suite are run one by one, so every test starts when the previous one stops
+	start := suiteStart(ext)
+	for i, test := range suite.Tests {
+		test := test
 		msg := test.Message
 		if test.Error != nil {
 			msg = msg + "\n" + test.Error.Error()
 		}
-		labels := suiteLabels(suite, parentSuite)
+		tc := matchTest(ext, i, test)
+		labels := p.suiteLabels(suite, parentSuite, test)
 
+		testStart := start
+		if tc != nil {
+			if t, ok := parseTimestamp(tc.Timestamp); ok {
+				testStart = t
+			}
+		}
 		ar := models.AllureResult{
 			Name:   test.Name,
-			Status: string(test.Status),
+			Status: convertStatus(test.Status),
 			StatusDetails: &models.StatusDetails{
 				Known:   false,
 				Muted:   false,
@@ -63,17 +145,19 @@ func convertTests(suite junit.Suite, parentSuite string) []models.AllureResult {
 				Message: &msg,
 				Trace:   &test.SystemErr,
 			},
-			Steps: []*models.Step{
-				{
-					Name: test.SystemOut,
-				},
-			},
-			Start:    0,
-			Stop:     test.Duration.Milliseconds(),
+			Steps:    p.Steps.Steps(outputLines(p.Steps.Source(), test, tc), convertStatus(test.Status)),
+			Start:    testStart,
+			Stop:     testStart + test.Duration.Milliseconds(),
 			UUID:     uuid.New(),
 			FullName: &test.Classname,
 			Labels:   labels,
 		}
+		if start != 0 {
+			start = ar.Stop
+		}
+		if ext != nil && ext.Hostname != "" {
+			ar.Labels = append(ar.Labels, &models.Label{Name: "host", Value: ext.Hostname})
+		}
 
 		for k, v := range test.Properties {
 			ar.Labels = append(ar.Labels, &models.Label{
@@ -81,42 +165,79 @@ func convertTests(suite junit.Suite, parentSuite string) []models.AllureResult {
 				Value: v,
 			})
 		}
-		res = append(res, ar)
+		attempts := applyReruns(&ar, tc)
+		res = append(res, testResult{AllureResult: ar, attempts: attempts})
 	}
-	for _, s := range suite.Suites {
-		res = append(res, convertTests(s, suite.Name)...)
+	for i, s := range suite.Suites {
+		var nested *models.JunitTestSuite
+		if ext != nil {
+			nested = matchSuite(ext.TestSuites, i, s)
+		}
+		res = append(res, p.convertTests(s, suite.Name, nested)...)
 	}
 	return res
 }
 
-func suiteLabels(suite junit.Suite, parentSuite string) []*models.Label {
-	if len(suite.Name) > 0 && suite.Name[0] == '.' {
-		return flutterLabels(suite, parentSuite)
+// outputLines returns lines of the test output the steps are read from.
+// Steps in properties are properties with names starting with `step`, in the order of the report.
+func outputLines(source string, test junit.Test, tc *models.JunitTestCase) []string {
+	switch source {
+	case markers.SystemErr:
+		return strings.Split(test.SystemErr, "\n")
+	case markers.Properties:
+		lines := []string{}
+		if tc == nil {
+			return lines
+		}
+		for _, p := range tc.Properties {
+			if strings.HasPrefix(strings.ToLower(p.Name), "step") {
+				lines = append(lines, p.Value)
+			}
+		}
+		return lines
+	default:
+		return strings.Split(test.SystemOut, "\n")
+	}
+}
+
+// suiteLabels derives suite labels of the test with the label rules,
+// labels no rule derived are the suite name and the parent suite.
+func (p Parser) suiteLabels(suite junit.Suite, parentSuite string, test junit.Test) []*models.Label {
+	fields := map[string]string{
+		labelrules.Name:        test.Name,
+		labelrules.ClassName:   test.Classname,
+		labelrules.Suite:       suite.Name,
+		labelrules.Package:     suite.Package,
+		labelrules.ParentSuite: parentSuite,
+	}
+	// go-junit keeps attributes of the suite or its properties if there are any, and attributes of the test
+	for _, props := range []map[string]string{suite.Properties, test.Properties} {
+		for k, v := range props {
+			fields[labelrules.Property+k] = v
+			if k == labelrules.File {
+				fields[labelrules.File] = v
+			}
+		}
+	}
+
+	labels := p.Labels.Apply(fields)
+	derived := map[string]bool{}
+	for _, l := range labels {
+		derived[l.Name] = true
+	}
+	if !derived["suite"] {
+		labels = append(labels, &models.Label{Name: "suite", Value: suite.Name})
 	}
-	return []*models.Label{
-		{
-			Name:  "suite",
-			Value: suite.Name,
-		},
-		{
-			Name:  "parentSuite",
-			Value: parentSuite,
-		},
+	if !derived["parentSuite"] {
+		labels = append(labels, &models.Label{Name: "parentSuite", Value: parentSuite})
 	}
+	return labels
 }
 
-func flutterLabels(suite junit.Suite, parentSuite string) []*models.Label {
-	splittedName := strings.Split(suite.Name, ".")
-	name := splittedName[len(splittedName)-1]
-
-	return []*models.Label{
-		{
-			Name:  "suite",
-			Value: name,
-		},
-		{
-			Name:  "parentSuite",
-			Value: suite.Package,
-		},
+// convertStatus returns the allure status of the junit test, tests with errors are broken like in allure.
+func convertStatus(status junit.Status) string {
+	if status == junit.StatusError {
+		return "broken"
 	}
+	return string(status)
 }
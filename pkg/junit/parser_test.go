@@ -0,0 +1,120 @@
+package junit
+
+import (
+	"strings"
+	"test-inspector/pkg/diag"
+	"test-inspector/pkg/models"
+	"testing"
+	"time"
+)
+
+func byName(t *testing.T, results map[string]models.SupaResult, name string) models.SupaResult {
+	t.Helper()
+	r, ok := results[name]
+	if !ok {
+		t.Fatalf("no result %s", name)
+	}
+	return r
+}
+
+func readTestdata(t *testing.T) map[string]models.SupaResult {
+	t.Helper()
+	results, err := ReadResults("testdata")
+	d, fatal := diag.From(err)
+	if fatal != nil {
+		t.Fatal(fatal)
+	}
+	if d.Errors() != 1 || d[0].File != "testdata/TEST-broken.xml" {
+		t.Errorf("diagnostics = %v, want the error of the broken report", d)
+	}
+	res := map[string]models.SupaResult{}
+	for _, r := range results {
+		res[r.Name] = r
+	}
+	return res
+}
+
+func TestReadResultsSurefire(t *testing.T) {
+	suiteStart := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC).UnixMilli()
+	tests := []struct {
+		name        string
+		status      string
+		flaky       bool
+		attempts    int16
+		start, stop int64
+		trace       []string
+	}{
+		{name: "signIn", status: "passed", attempts: 1, start: suiteStart, stop: suiteStart + 1500},
+		{name: "signOut", status: "passed", flaky: true, attempts: 2, start: suiteStart + 1500, stop: suiteStart + 2000,
+			trace: []string{"flaky run 1: java.lang.AssertionError: session expired", "AuthTests.java:42"}},
+		{name: "resetPassword", status: "failed", attempts: 3, start: suiteStart + 2000, stop: suiteStart + 4500,
+			trace: []string{"rerun 1: java.lang.AssertionError: mail not sent",
+				"rerun 2: java.net.SocketTimeoutException: smtp timeout"}},
+	}
+	results := readTestdata(t)
+	if len(results) != len(tests) {
+		t.Fatalf("%d results, want %d", len(results), len(tests))
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := byName(t, results, tt.name)
+			if r.Status != tt.status || r.StatusDetails.Flaky != tt.flaky || r.Attempts != tt.attempts {
+				t.Errorf("status %s, flaky %v, attempts %d, want %s, %v, %d",
+					r.Status, r.StatusDetails.Flaky, r.Attempts, tt.status, tt.flaky, tt.attempts)
+			}
+			if r.Start != tt.start || r.Stop != tt.stop {
+				t.Errorf("start %d, stop %d, want %d, %d", r.Start, r.Stop, tt.start, tt.stop)
+			}
+			for _, want := range tt.trace {
+				if !strings.Contains(*r.StatusDetails.Trace, want) {
+					t.Errorf("trace %q does not contain %q", *r.StatusDetails.Trace, want)
+				}
+			}
+			if r.FullName != "com.example.AuthTests" || r.Suite != "com.example.AuthTests" {
+				t.Errorf("full name %s, suite %s", r.FullName, r.Suite)
+			}
+			// environment properties of the suite are not parameters of tests
+			if len(r.Parameters) != 0 {
+				t.Errorf("parameters %v, want none", r.Parameters)
+			}
+			host := false
+			for _, l := range r.Labels {
+				host = host || l.Name == "host" && l.Value == "ci-runner-1"
+			}
+			if !host {
+				t.Error("no host label")
+			}
+		})
+	}
+}
+
+func TestReadResultsSteps(t *testing.T) {
+	r := byName(t, readTestdata(t), "signIn")
+	for _, step := range []string{"open login page", "submit credentials"} {
+		if !strings.Contains(r.Steps, step) {
+			t.Errorf("steps %s do not contain %q", r.Steps, step)
+		}
+	}
+}
+
+func TestParseTimestamp(t *testing.T) {
+	want := time.Date(2024, 5, 1, 10, 0, 0, 500000000, time.UTC).UnixMilli()
+	tests := []struct {
+		timestamp string
+		want      int64
+		ok        bool
+	}{
+		{"2024-05-01T10:00:00.5Z", want, true},
+		{"2024-05-01T12:00:00.5+02:00", want, true},
+		{"2024-05-01T10:00:00.500", want, true},
+		{"2024-05-01 10:00:00.5", want, true},
+		{"", 0, false},
+		{"yesterday", 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := parseTimestamp(tt.timestamp)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("parseTimestamp(%q) = %d, %v, want %d, %v", tt.timestamp, got, ok, tt.want, tt.ok)
+		}
+	}
+}
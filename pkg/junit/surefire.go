@@ -0,0 +1,113 @@
+package junit
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+	"test-inspector/pkg/models"
+	"time"
+
+	"github.com/joshdk/go-junit"
+)
+
+// timestampLayouts are layouts of suite and test timestamps, junit reporters usually omit the time zone.
+var timestampLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05.999999999",
+}
+
+// parseSurefire reads suites of the report with the parts go-junit skips:
+// timestamps, hosts and maven surefire reruns.
+func parseSurefire(data []byte) []*models.JunitTestSuite {
+	var suites models.JunitTestSuites
+	if err := xml.Unmarshal(data, &suites); err == nil {
+		return suites.TestSuites
+	}
+	var suite models.JunitTestSuite
+	if err := xml.Unmarshal(data, &suite); err == nil {
+		return []*models.JunitTestSuite{&suite}
+	}
+	return nil
+}
+
+func matchSuite(suites []*models.JunitTestSuite, i int, suite junit.Suite) *models.JunitTestSuite {
+	if i < len(suites) && suites[i].Name == suite.Name {
+		return suites[i]
+	}
+	return nil
+}
+
+func matchTest(suite *models.JunitTestSuite, i int, test junit.Test) *models.JunitTestCase {
+	if suite != nil && i < len(suite.TestCases) && suite.TestCases[i].Name == test.Name {
+		return suite.TestCases[i]
+	}
+	return nil
+}
+
+// parseTimestamp returns the timestamp in milliseconds since the epoch, timestamps without the zone are in UTC.
+func parseTimestamp(timestamp string) (int64, bool) {
+	if timestamp == "" {
+		return 0, false
+	}
+	for _, layout := range timestampLayouts {
+		if t, err := time.Parse(layout, timestamp); err == nil {
+			return t.UnixMilli(), true
+		}
+	}
+	return 0, false
+}
+
+func suiteStart(suite *models.JunitTestSuite) int64 {
+	if suite == nil {
+		return 0
+	}
+	start, _ := parseTimestamp(suite.Timestamp)
+	return start
+}
+
+// applyReruns adds failed runs of the test to the trace and returns the number of runs.
+// A passed test with flaky failures is marked flaky, its message is taken from the first failure.
+func applyReruns(ar *models.AllureResult, tc *models.JunitTestCase) int {
+	if tc == nil {
+		return 1
+	}
+	flaky := append(append([]*models.JunitRerun{}, tc.FlakyFailures...), tc.FlakyErrors...)
+	reruns := append(append([]*models.JunitRerun{}, tc.RerunFailures...), tc.RerunErrors...)
+	if len(flaky) == 0 && len(reruns) == 0 {
+		return 1
+	}
+
+	if len(flaky) > 0 && ar.Status == string(junit.StatusPassed) {
+		ar.StatusDetails.Flaky = true
+		if strings.TrimSpace(*ar.StatusDetails.Message) == "" {
+			message := flaky[0].Message
+			ar.StatusDetails.Message = &message
+		}
+	}
+	history := []string{}
+	if *ar.StatusDetails.Trace != "" {
+		history = append(history, *ar.StatusDetails.Trace)
+	}
+	for i, r := range flaky {
+		history = append(history, fmt.Sprintf("flaky run %d: %s", i+1, rerunDetails(r)))
+	}
+	for i, r := range reruns {
+		history = append(history, fmt.Sprintf("rerun %d: %s", i+1, rerunDetails(r)))
+	}
+	trace := strings.Join(history, "\n")
+	ar.StatusDetails.Trace = &trace
+	return 1 + len(flaky) + len(reruns)
+}
+
+func rerunDetails(r *models.JunitRerun) string {
+	details := strings.TrimSpace(r.Type + ": " + r.Message)
+	stack := strings.TrimSpace(r.StackTrace)
+	if stack == "" {
+		stack = strings.TrimSpace(r.Body)
+	}
+	if stack != "" {
+		details += "\n" + stack
+	}
+	return details
+}
@@ -0,0 +1,137 @@
+// Package labelmap maps arbitrary labels of the test onto the feature and suite fields of the result,
+// so ports that use other labels for the hierarchy, for ex. `epic` and `story`, line up with the reference.
+package labelmap
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"test-inspector/pkg/models"
+)
+
+// Fields of the result that can be mapped.
+const (
+	Feature     = "feature"
+	ParentSuite = "parentSuite"
+	Suite       = "suite"
+	SubSuite    = "subSuite"
+)
+
+var fields = []string{Feature, ParentSuite, Suite, SubSuite}
+
+// Config is the configuration of the mapping in the config file, expressions by the field name.
+// An expression is a fallback chain of alternatives separated by `??`, the first non-empty one wins.
+// An alternative is a label name or a quoted template like `'${epic} / ${story}'`,
+// a template is skipped if one of its labels is empty, so a template without labels is a default value.
+type Config map[string]string
+
+// Mapping is a compiled mapping of labels onto result fields.
+type Mapping struct {
+	chains map[string][]alternative
+}
+
+// alternative is a single label or template of the fallback chain.
+type alternative struct {
+	label    string
+	template string
+	isTmpl   bool
+}
+
+// Compile checks the config and compiles the expressions.
+// Field names are matched case-insensitively as the config file keys are lowercased.
+func Compile(cfg Config) (*Mapping, error) {
+	m := &Mapping{chains: map[string][]alternative{}}
+	for key, expr := range cfg {
+		field, ok := fieldName(key)
+		if !ok {
+			return nil, fmt.Errorf("unknown mapping field '%s', possible values: %s",
+				key, strings.Join(fields, ", "))
+		}
+		chain, err := parse(expr)
+		if err != nil {
+			return nil, fmt.Errorf("bad mapping of %s: %v", field, err)
+		}
+		m.chains[field] = chain
+	}
+	return m, nil
+}
+
+func fieldName(key string) (string, bool) {
+	for _, f := range fields {
+		if strings.EqualFold(f, key) {
+			return f, true
+		}
+	}
+	return "", false
+}
+
+func parse(expr string) ([]alternative, error) {
+	chain := []alternative{}
+	for _, part := range strings.Split(expr, "??") {
+		part = strings.TrimSpace(part)
+		switch {
+		case part == "":
+			return nil, fmt.Errorf("empty alternative in '%s'", expr)
+		case part[0] == '\'' || part[0] == '"':
+			if len(part) < 2 || part[len(part)-1] != part[0] {
+				return nil, fmt.Errorf("unterminated template %s", part)
+			}
+			chain = append(chain, alternative{template: part[1 : len(part)-1], isTmpl: true})
+		case strings.ContainsAny(part, " \t'\"${}"):
+			return nil, fmt.Errorf("bad label name '%s', templates should be quoted", part)
+		default:
+			chain = append(chain, alternative{label: part})
+		}
+	}
+	return chain, nil
+}
+
+// Apply sets the mapped fields of the result from its labels, fields without mapping are kept as is.
+// The field becomes empty if no alternative of the chain has a value.
+func (m *Mapping) Apply(r *models.SupaResult) {
+	if m == nil || len(m.chains) == 0 {
+		return
+	}
+	labels := map[string]string{}
+	for _, l := range r.Labels {
+		if _, ok := labels[l.Name]; !ok {
+			labels[l.Name] = l.Value
+		}
+	}
+	for field, chain := range m.chains {
+		value := evaluate(chain, labels)
+		switch field {
+		case Feature:
+			r.Feature = value
+		case ParentSuite:
+			r.ParentSuite = value
+		case Suite:
+			r.Suite = value
+		case SubSuite:
+			r.SubSuite = value
+		}
+	}
+}
+
+func evaluate(chain []alternative, labels map[string]string) string {
+	for _, alt := range chain {
+		if !alt.isTmpl {
+			if v := labels[alt.label]; v != "" {
+				return v
+			}
+			continue
+		}
+		complete := true
+		value := os.Expand(alt.template, func(label string) string {
+			v := labels[label]
+			if v == "" {
+				complete = false
+			}
+			return v
+		})
+		if complete && value != "" {
+			return value
+		}
+	}
+	return ""
+}
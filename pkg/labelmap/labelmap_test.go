@@ -0,0 +1,88 @@
+package labelmap
+
+import (
+	"strings"
+	"test-inspector/pkg/models"
+	"testing"
+)
+
+func TestApply(t *testing.T) {
+	labels := []*models.Label{
+		{Name: "epic", Value: "Accounts"},
+		{Name: "story", Value: "Sign in"},
+		{Name: "package", Value: "tests.auth"},
+		{Name: "testClass", Value: "TestSignIn"},
+		{Name: "owner", Value: ""},
+		// the first label with the name wins
+		{Name: "epic", Value: "Billing"},
+	}
+	tests := []struct {
+		name string
+		cfg  Config
+		want models.SupaResult
+	}{
+		{
+			name: "labels",
+			cfg:  Config{"parentsuite": "package", "suite": "testClass"},
+			want: models.SupaResult{Feature: "feature", ParentSuite: "tests.auth", Suite: "TestSignIn", SubSuite: "sub"},
+		},
+		{
+			name: "fallback chain",
+			cfg:  Config{"feature": "feature ?? owner ?? epic ?? package"},
+			want: models.SupaResult{Feature: "Accounts", ParentSuite: "parent", Suite: "suite", SubSuite: "sub"},
+		},
+		{
+			name: "template",
+			cfg:  Config{"subSuite": `'${epic} / ${story}'`},
+			want: models.SupaResult{Feature: "feature", ParentSuite: "parent", Suite: "suite", SubSuite: "Accounts / Sign in"},
+		},
+		{
+			// a template with an empty label is skipped, a template without labels is a default value
+			name: "incomplete template",
+			cfg:  Config{"suite": `'${owner}: ${story}' ?? "unowned"`},
+			want: models.SupaResult{Feature: "feature", ParentSuite: "parent", Suite: "unowned", SubSuite: "sub"},
+		},
+		{
+			name: "no value",
+			cfg:  Config{"feature": "feature ?? owner"},
+			want: models.SupaResult{Feature: "", ParentSuite: "parent", Suite: "suite", SubSuite: "sub"},
+		},
+		{
+			name: "no mapping",
+			cfg:  Config{},
+			want: models.SupaResult{Feature: "feature", ParentSuite: "parent", Suite: "suite", SubSuite: "sub"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := Compile(tt.cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			r := models.SupaResult{Feature: "feature", ParentSuite: "parent", Suite: "suite", SubSuite: "sub", Labels: labels}
+			m.Apply(&r)
+			got := [4]string{r.Feature, r.ParentSuite, r.Suite, r.SubSuite}
+			want := [4]string{tt.want.Feature, tt.want.ParentSuite, tt.want.Suite, tt.want.SubSuite}
+			if got != want {
+				t.Errorf("feature, parent suite, suite, sub suite = %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+	tests := []struct {
+		cfg  Config
+		want string
+	}{
+		{Config{"epic": "story"}, "unknown mapping field 'epic'"},
+		{Config{"feature": "epic ?? "}, "empty alternative"},
+		{Config{"feature": "'${epic}"}, "unterminated template"},
+		{Config{"feature": "${epic}"}, "templates should be quoted"},
+	}
+	for _, tt := range tests {
+		if _, err := Compile(tt.cfg); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("Compile(%v) = %v, want %q", tt.cfg, err, tt.want)
+		}
+	}
+}
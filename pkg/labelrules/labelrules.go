@@ -0,0 +1,206 @@
+// Package labelrules derives suite labels of tests from their class names, packages, suite names
+// and properties, so reports of different frameworks line up with the reference hierarchy.
+package labelrules
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"test-inspector/pkg/models"
+)
+
+// Fields of the test rules are matched against, properties are matched as `property.<name>`.
+const (
+	Name        = "name"
+	ClassName   = "classname"
+	Suite       = "suite"
+	Package     = "package"
+	ParentSuite = "parentSuite"
+	File        = "file"
+	Property    = "property."
+)
+
+// Default is the preset used when no preset is configured.
+const Default = "default"
+
+// flutter suites are test files with the path joined by dots, for ex. `.test.auth.sign_in_test`
+var flutter = Rule{Match: map[string]string{
+	Suite:   `^\.(?:.*\.)?(?P<suite>[^.]*)$`,
+	Package: `^(?P<parentSuite>.*)$`,
+}}
+
+// presets are rules of test frameworks and junit reporters.
+var presets = map[string][]Rule{
+	Default:   {flutter},
+	"flutter": {flutter},
+	// `tests.auth.test_sign_in.TestSignIn`: package, module and class
+	"pytest": {
+		{Match: map[string]string{
+			ClassName: `^(?:(?P<parentSuite>.+)\.)?(?P<suite>test_[^.]+|[^.]+_test)(?:\.(?P<subSuite>.+))?$`,
+		}},
+	},
+	// suites are top level describe blocks, parent suite is the test file if `addFileAttribute` is on
+	"jest-junit": {
+		{Match: map[string]string{
+			Suite: `^(?P<suite>.+)$`,
+			File:  `(?:^|/)(?P<parentSuite>[^/]+?)(?:\.(?:test|spec))?\.[cm]?[jt]sx?$`,
+		}},
+		{Match: map[string]string{
+			Suite: `^(?P<suite>.+)$`,
+		}},
+	},
+	// suites are packages, tests are `TestName/subtest`, the same as for `go test -json`
+	"go-junit-report": {
+		{Match: map[string]string{
+			Suite: `^(?P<parentSuite>.+)$`,
+			Name:  `^(?P<suite>[^/]+)(?:/(?P<subSuite>.+))?$`,
+		}},
+	},
+	// class names are namespaces with the class joined by dots, for ex. `Tests.Unit.AuthTest`
+	"phpunit": {
+		{Match: map[string]string{
+			ClassName: `^(?:(?P<parentSuite>.+)\.)?(?P<suite>[^.]+)$`,
+		}},
+	},
+	// class names are `TestTarget.TestClass`
+	"swift-xcresult": {
+		{Match: map[string]string{
+			ClassName: `^(?P<parentSuite>[^.]+)\.(?P<suite>.+)$`,
+		}},
+	},
+}
+
+// Rule derives labels of the test if all its patterns match the fields of the test.
+// Named groups of patterns become labels, labels templates can use groups as `${group}`.
+// @property Match - Patterns by the field name.
+// @property Labels - Label templates by the label name.
+type Rule struct {
+	Match  map[string]string `mapstructure:"match"`
+	Labels map[string]string `mapstructure:"labels"`
+}
+
+// Config is the configuration of label rules in the config file.
+// @property {string} Preset - The name of the preset, see Presets.
+// @property {[]Rule} Rules - Custom rules, tried before the preset ones.
+type Config struct {
+	Preset string `mapstructure:"preset"`
+	Rules  []Rule `mapstructure:"rules"`
+}
+
+// Rules are compiled label rules, the first rule that derives the label wins.
+type Rules struct {
+	rules []*compiledRule
+}
+
+type compiledRule struct {
+	match  map[string]*regexp.Regexp
+	fields []string
+	labels map[string]string
+}
+
+// Presets returns names of all presets.
+func Presets() []string {
+	names := make([]string, 0, len(presets))
+	for name := range presets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Compile checks the config and compiles the rules.
+func Compile(cfg Config) (*Rules, error) {
+	preset := cfg.Preset
+	if preset == "" {
+		preset = Default
+	}
+	presetRules, ok := presets[preset]
+	if !ok {
+		return nil, fmt.Errorf("unknown labels preset '%s', possible values: %s",
+			preset, strings.Join(Presets(), ", "))
+	}
+	rules := &Rules{}
+	for _, rule := range append(append([]Rule{}, cfg.Rules...), presetRules...) {
+		if len(rule.Match) == 0 {
+			return nil, fmt.Errorf("label rule should match at least one field")
+		}
+		c := &compiledRule{match: map[string]*regexp.Regexp{}, labels: rule.Labels}
+		for field, pattern := range rule.Match {
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				return nil, fmt.Errorf("bad label rule pattern %s: %v", pattern, err)
+			}
+			c.match[field] = re
+			c.fields = append(c.fields, field)
+		}
+		sort.Strings(c.fields)
+		rules.rules = append(rules.rules, c)
+	}
+	return rules, nil
+}
+
+// MustCompile is like Compile but panics if the config is wrong, it is used for presets.
+func MustCompile(cfg Config) *Rules {
+	rules, err := Compile(cfg)
+	if err != nil {
+		panic(err)
+	}
+	return rules
+}
+
+// Apply derives labels from the fields of the test. Labels are returned even with empty values
+// when optional groups do not match, so the parser does not fall back to its own labels.
+func (r *Rules) Apply(fields map[string]string) []*models.Label {
+	derived := map[string]string{}
+	for _, rule := range r.rules {
+		groups, ok := rule.apply(fields)
+		if !ok {
+			continue
+		}
+		for name, value := range groups {
+			if _, ok := derived[name]; !ok {
+				derived[name] = value
+			}
+		}
+		for name, template := range rule.labels {
+			if _, ok := derived[name]; !ok {
+				derived[name] = os.Expand(template, func(group string) string { return groups[group] })
+			}
+		}
+	}
+
+	names := make([]string, 0, len(derived))
+	for name := range derived {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	labels := []*models.Label{}
+	for _, name := range names {
+		labels = append(labels, &models.Label{Name: name, Value: derived[name]})
+	}
+	return labels
+}
+
+// apply returns named groups of all patterns if all of them match.
+func (c *compiledRule) apply(fields map[string]string) (map[string]string, bool) {
+	groups := map[string]string{}
+	for _, field := range c.fields {
+		value, ok := fields[field]
+		if !ok {
+			return nil, false
+		}
+		re := c.match[field]
+		m := re.FindStringSubmatch(value)
+		if m == nil {
+			return nil, false
+		}
+		for i, name := range re.SubexpNames() {
+			if name != "" {
+				groups[name] = m[i]
+			}
+		}
+	}
+	return groups, true
+}
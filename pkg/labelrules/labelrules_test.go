@@ -0,0 +1,146 @@
+package labelrules
+
+import (
+	"reflect"
+	"strings"
+	"test-inspector/pkg/models"
+	"testing"
+)
+
+func TestApplyPresets(t *testing.T) {
+	tests := []struct {
+		preset string
+		fields map[string]string
+		want   map[string]string
+	}{
+		{
+			preset: "flutter",
+			fields: map[string]string{Suite: ".test.auth.sign_in_test", Package: "app"},
+			want:   map[string]string{"suite": "sign_in_test", "parentSuite": "app"},
+		},
+		{
+			// flutter suites are detected by the default preset as well
+			preset: "",
+			fields: map[string]string{Suite: ".sign_in_test", Package: "app"},
+			want:   map[string]string{"suite": "sign_in_test", "parentSuite": "app"},
+		},
+		{
+			preset: "",
+			fields: map[string]string{Suite: "AuthTests", Package: "app"},
+			want:   map[string]string{},
+		},
+		{
+			preset: "pytest",
+			fields: map[string]string{ClassName: "tests.auth.test_sign_in.TestSignIn"},
+			want:   map[string]string{"parentSuite": "tests.auth", "suite": "test_sign_in", "subSuite": "TestSignIn"},
+		},
+		{
+			// optional groups derive empty labels, so parsers do not fall back to their own
+			preset: "pytest",
+			fields: map[string]string{ClassName: "test_health"},
+			want:   map[string]string{"parentSuite": "", "suite": "test_health", "subSuite": ""},
+		},
+		{
+			preset: "jest-junit",
+			fields: map[string]string{Suite: "Auth", File: "src/auth/sign-in.test.tsx"},
+			want:   map[string]string{"parentSuite": "sign-in", "suite": "Auth"},
+		},
+		{
+			preset: "jest-junit",
+			fields: map[string]string{Suite: "Auth"},
+			want:   map[string]string{"suite": "Auth"},
+		},
+		{
+			preset: "go-junit-report",
+			fields: map[string]string{Suite: "example.com/auth", Name: "TestSignIn/wrong_password/reports_error"},
+			want:   map[string]string{"parentSuite": "example.com/auth", "suite": "TestSignIn", "subSuite": "wrong_password/reports_error"},
+		},
+		{
+			preset: "phpunit",
+			fields: map[string]string{ClassName: "Tests.Unit.AuthTest"},
+			want:   map[string]string{"parentSuite": "Tests.Unit", "suite": "AuthTest"},
+		},
+		{
+			preset: "swift-xcresult",
+			fields: map[string]string{ClassName: "AppTests.SignInTests"},
+			want:   map[string]string{"parentSuite": "AppTests", "suite": "SignInTests"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.preset+" "+tt.fields[Suite]+tt.fields[ClassName], func(t *testing.T) {
+			rules, err := Compile(Config{Preset: tt.preset})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := labels(rules.Apply(tt.fields)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("labels %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyCustomRules(t *testing.T) {
+	rules, err := Compile(Config{
+		Preset: "phpunit",
+		Rules: []Rule{
+			{
+				Match:  map[string]string{Property + "epic": `^(?P<epic>.+)$`, ClassName: `\.(?P<class>[^.]+)$`},
+				Labels: map[string]string{"feature": "${epic}: ${class}"},
+			},
+			{
+				Match: map[string]string{ClassName: `^Legacy\.(?P<suite>.+)$`},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		fields map[string]string
+		want   map[string]string
+	}{
+		{
+			// custom rules are tried before the preset ones
+			fields: map[string]string{ClassName: "Legacy.Auth.AuthTest"},
+			want:   map[string]string{"parentSuite": "Legacy.Auth", "suite": "Auth.AuthTest"},
+		},
+		{
+			fields: map[string]string{ClassName: "Tests.AuthTest", Property + "epic": "Accounts"},
+			want:   map[string]string{"epic": "Accounts", "class": "AuthTest", "feature": "Accounts: AuthTest", "parentSuite": "Tests", "suite": "AuthTest"},
+		},
+		{
+			// all patterns of the rule should match
+			fields: map[string]string{ClassName: "Tests.AuthTest"},
+			want:   map[string]string{"parentSuite": "Tests", "suite": "AuthTest"},
+		},
+	}
+	for _, tt := range tests {
+		if got := labels(rules.Apply(tt.fields)); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Apply(%v) = %v, want %v", tt.fields, got, tt.want)
+		}
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+	tests := []struct {
+		cfg  Config
+		want string
+	}{
+		{Config{Preset: "nose"}, "unknown labels preset 'nose'"},
+		{Config{Rules: []Rule{{Labels: map[string]string{"suite": "x"}}}}, "should match at least one field"},
+		{Config{Rules: []Rule{{Match: map[string]string{Suite: "(?P<suite"}}}}, "bad label rule pattern"},
+	}
+	for _, tt := range tests {
+		if _, err := Compile(tt.cfg); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("Compile(%+v) = %v, want %q", tt.cfg, err, tt.want)
+		}
+	}
+}
+
+func labels(l []*models.Label) map[string]string {
+	res := map[string]string{}
+	for _, label := range l {
+		res[label.Name] = label.Value
+	}
+	return res
+}
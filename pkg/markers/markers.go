@@ -0,0 +1,199 @@
+// Package markers builds test steps from the output of the test by matching step marker lines,
+// for reports like junit that do not have steps of their own.
+package markers
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"test-inspector/pkg/models"
+)
+
+// Sources of the lines steps are read from.
+const (
+	SystemOut  = "system-out"
+	SystemErr  = "system-err"
+	Properties = "properties"
+)
+
+// Default is the preset used when no preset or markers are configured.
+const Default = "default"
+
+// presets are step markers of test frameworks. The `name` group is the name of the step,
+// the optional `status` group is its status. Steps are nested by the length of the `depth` group,
+// the width of the `indent` group or the indentation of the line.
+var presets = map[string][]string{
+	// bare `>` lines are not markers, shell echoes and quoted diffs start with them too
+	Default: {
+		`^\s*STEP:\s*(?P<name>.+?)\s*$`,
+		`^\s*STEP(?P<depth>>+)\s*(?P<name>.+?)\s*$`,
+	},
+	// pytest writes captured logs as `INFO     logger:test_file.py:12 STEP: name`
+	"pytest": {
+		`^(?:[A-Z]+\s+\S+:\S+:\d+ )?(?P<indent>\s*)(?i:step):\s*(?P<name>.+?)\s*$`,
+	},
+	// dart test prints are kept as is, nested steps are indented
+	"dart": {
+		`^(?P<indent>\s*)>>\s*(?P<name>.+?)\s*$`,
+	},
+	// phpunit testdox output and `Step:` lines
+	"phpunit": {
+		`^(?P<indent>\s*)(?P<status>[✔✘↩∅☢])\s+(?P<name>.+?)\s*$`,
+		`^(?P<indent>\s*)(?i:step):\s*(?P<name>.+?)\s*$`,
+	},
+}
+
+// Config is the configuration of step markers in the config file.
+// @property {string} Preset - The name of the preset, see Presets.
+// @property {string} Source - Where to read the lines from: system-out, system-err or properties.
+// @property {[]string} Markers - Custom marker patterns, tried before the preset ones.
+type Config struct {
+	Preset  string   `mapstructure:"preset"`
+	Source  string   `mapstructure:"source"`
+	Markers []string `mapstructure:"markers"`
+}
+
+// Rules are compiled step markers.
+type Rules struct {
+	source  string
+	markers []*regexp.Regexp
+}
+
+// Presets returns names of all presets.
+func Presets() []string {
+	names := make([]string, 0, len(presets))
+	for name := range presets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Compile checks the config and compiles the markers.
+func Compile(cfg Config) (*Rules, error) {
+	rules := &Rules{source: cfg.Source}
+	switch rules.source {
+	case "":
+		rules.source = SystemOut
+	case SystemOut, SystemErr, Properties:
+	default:
+		return nil, fmt.Errorf("unsupported steps source '%s', possible values: %s",
+			cfg.Source, strings.Join([]string{SystemOut, SystemErr, Properties}, ", "))
+	}
+
+	patterns := cfg.Markers
+	if cfg.Preset != "" || len(cfg.Markers) == 0 {
+		preset := cfg.Preset
+		if preset == "" {
+			preset = Default
+		}
+		presetPatterns, ok := presets[preset]
+		if !ok {
+			return nil, fmt.Errorf("unknown steps preset '%s', possible values: %s",
+				preset, strings.Join(Presets(), ", "))
+		}
+		patterns = append(append([]string{}, patterns...), presetPatterns...)
+	}
+	for _, p := range patterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return nil, fmt.Errorf("bad step marker %s: %v", p, err)
+		}
+		if re.SubexpIndex("name") < 0 {
+			return nil, fmt.Errorf("step marker %s has no `name` group", p)
+		}
+		rules.markers = append(rules.markers, re)
+	}
+	return rules, nil
+}
+
+// MustCompile is like Compile but panics if the config is wrong, it is used for presets.
+func MustCompile(cfg Config) *Rules {
+	rules, err := Compile(cfg)
+	if err != nil {
+		panic(err)
+	}
+	return rules
+}
+
+// Source returns where the lines with steps should be read from.
+func (r *Rules) Source() string {
+	return r.source
+}
+
+// step is a matched step with its nesting level.
+type step struct {
+	level int
+	step  *models.Step
+}
+
+// Steps builds the tree of steps from the lines, lines without markers are skipped.
+// Steps without the status get the default one, usually the status of the test.
+func (r *Rules) Steps(lines []string, defaultStatus string) []*models.Step {
+	root := &models.Step{Steps: []*models.Step{}}
+	stack := []step{{level: -1, step: root}}
+	for _, line := range lines {
+		s, level, ok := r.match(line, defaultStatus)
+		if !ok {
+			continue
+		}
+		for len(stack) > 1 && stack[len(stack)-1].level >= level {
+			stack = stack[:len(stack)-1]
+		}
+		parent := stack[len(stack)-1].step
+		parent.Steps = append(parent.Steps, s)
+		stack = append(stack, step{level: level, step: s})
+	}
+	return root.Steps
+}
+
+func (r *Rules) match(line, defaultStatus string) (*models.Step, int, bool) {
+	line = strings.TrimRight(line, "\r")
+	for _, re := range r.markers {
+		m := re.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		status := defaultStatus
+		if i := re.SubexpIndex("status"); i >= 0 && m[i] != "" {
+			status = convertStatus(m[i])
+		}
+		return &models.Step{
+			Name:   m[re.SubexpIndex("name")],
+			Status: &status,
+			Steps:  []*models.Step{},
+		}, level(re, m, line), true
+	}
+	return nil, 0, false
+}
+
+// level returns the nesting level of the matched line.
+func level(re *regexp.Regexp, m []string, line string) int {
+	if i := re.SubexpIndex("depth"); i >= 0 {
+		return len(m[i])
+	}
+	if i := re.SubexpIndex("indent"); i >= 0 {
+		return width(m[i])
+	}
+	return width(line[:len(line)-len(strings.TrimLeft(line, " \t"))])
+}
+
+func width(indent string) int {
+	return len(strings.ReplaceAll(indent, "\t", "    "))
+}
+
+func convertStatus(status string) string {
+	switch strings.ToLower(status) {
+	case "✔", "✓", "ok", "pass", "passed":
+		return "passed"
+	case "✘", "✗", "fail", "failed":
+		return "failed"
+	case "☢", "error", "broken":
+		return "broken"
+	case "↩", "∅", "skip", "skipped":
+		return "skipped"
+	default:
+		return "unknown"
+	}
+}
@@ -0,0 +1,44 @@
+package markers
+
+import (
+	"reflect"
+	"test-inspector/pkg/models"
+	"testing"
+)
+
+func names(steps []*models.Step, prefix string) []string {
+	res := []string{}
+	for _, s := range steps {
+		res = append(res, prefix+s.Name)
+		res = append(res, names(s.Steps, prefix+"  ")...)
+	}
+	return res
+}
+
+func TestDefaultSteps(t *testing.T) {
+	tests := []struct {
+		name  string
+		lines []string
+		want  []string
+	}{
+		{
+			name:  "nested markers",
+			lines: []string{"STEP: open app", "STEP> login", "STEP>> type email", "STEP> logout"},
+			want:  []string{"open app", "  login", "    type email", "  logout"},
+		},
+		{
+			name:  "lines starting with > are output",
+			lines: []string{"> npm test", ">> quoted reply", "STEP: open app", "> echo done"},
+			want:  []string{"open app"},
+		},
+	}
+	rules := MustCompile(Config{})
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := names(rules.Steps(tt.lines, "passed"), "")
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Steps() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
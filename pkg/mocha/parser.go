@@ -0,0 +1,167 @@
+package mocha
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"test-inspector/pkg/diag"
+	"test-inspector/pkg/files"
+	"test-inspector/pkg/models"
+	"test-inspector/pkg/supatms"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Parser is a report parser for the mocha json reporter output.
+type Parser struct{}
+
+// Name returns the report type of mocha json reports.
+func (Parser) Name() string {
+	return "mocha"
+}
+
+// Detect checks if the path is a mocha json file or a folder containing one.
+func (Parser) Detect(resultsPath string) bool {
+	reports, err := files.List(resultsPath, ".json")
+	if err != nil {
+		return false
+	}
+	for _, f := range reports {
+		if _, err := parseFile(f); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// ReadResults reads mocha json report, see ReadResults.
+func (Parser) ReadResults(resultsPath string) (map[uuid.UUID]models.SupaResult, error) {
+	return ReadResults(resultsPath)
+}
+
+// ReadResults reads mocha json report file or all such files in the folder
+// and returns them as a map of SupaResults
+func ReadResults(resultsPath string) (map[uuid.UUID]models.SupaResult, error) {
+	reports, err := files.List(resultsPath, ".json")
+	if err != nil {
+		return nil, err
+	}
+
+	results := map[uuid.UUID]models.SupaResult{}
+	diagnostics := &diag.Collector{}
+	for _, f := range reports {
+		report, err := parseFile(f)
+		if err != nil {
+			// other json files are skipped silently, only broken ones are reported
+			if diag.Malformed(err) {
+				diagnostics.Add(diag.Skip(f, err))
+			}
+			continue
+		}
+		var start int64
+		if t, err := time.Parse(time.RFC3339Nano, report.Stats.Start); err == nil {
+			start = t.UnixMilli()
+		}
+		pending := map[string]bool{}
+		for _, test := range report.Pending {
+			pending[test.File+test.FullTitle] = true
+		}
+		chains := describeChains(report.Tests)
+		for _, test := range report.Tests {
+			titles := describeTitles(describeChain(test), chains[test.File])
+			res := supatms.ToResult(0, *convertTest(test, titles, pending[test.File+test.FullTitle], start), "")
+			results[res.ID] = res
+		}
+	}
+	return results, diagnostics.Err()
+}
+
+// parseFile reads the file and returns an error if it is not a mocha report.
+func parseFile(path string) (*models.MochaReport, error) {
+	jsonFile, err := files.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var report models.MochaReport
+	if err = json.Unmarshal(jsonFile, &report); err != nil {
+		return nil, err
+	}
+	if report.Stats == nil || report.Tests == nil {
+		return nil, fmt.Errorf("%s is not a mocha report", path)
+	}
+	return &report, nil
+}
+
+// describeChain returns titles of `describe` blocks of the test joined by mocha with spaces.
+func describeChain(test *models.MochaTest) string {
+	return strings.TrimSpace(strings.TrimSuffix(test.FullTitle, test.Title))
+}
+
+// describeChains returns describe chains of tests by their files.
+func describeChains(tests []*models.MochaTest) map[string]map[string]bool {
+	chains := map[string]map[string]bool{}
+	for _, test := range tests {
+		if chains[test.File] == nil {
+			chains[test.File] = map[string]bool{}
+		}
+		if chain := describeChain(test); chain != "" {
+			chains[test.File][chain] = true
+		}
+	}
+	return chains
+}
+
+// describeTitles splits the describe chain to titles of describe blocks. Mocha joins them with spaces,
+// so the chain is split after the longest chain of another test of the file it starts with:
+// `Auth login` is `Auth` and `login` if there are tests right in `Auth`. Blocks without own tests
+// can't be told apart from their children and stay joined with them.
+func describeTitles(chain string, chains map[string]bool) []string {
+	if chain == "" {
+		return nil
+	}
+	for i := len(chain) - 1; i > 0; i-- {
+		if chain[i] == ' ' && chains[chain[:i]] {
+			return append(describeTitles(chain[:i], chains), strings.TrimSpace(chain[i+1:]))
+		}
+	}
+	return []string{chain}
+}
+
+// convertTest converts mocha test to allure result. The file becomes the parent suite,
+// the outer describe block the suite and the rest of them the sub suite.
+func convertTest(test *models.MochaTest, describes []string, pending bool, start int64) *models.AllureResult {
+	suites := []string{}
+	if test.File != "" {
+		suites = append(suites, strings.TrimSuffix(filepath.Base(test.File), filepath.Ext(test.File)))
+	}
+	suites = append(suites, describes...)
+	fullName := test.FullTitle
+	ar := &models.AllureResult{
+		Name:     test.Title,
+		Status:   "passed",
+		Start:    start,
+		Stop:     start + test.Duration,
+		UUID:     uuid.New(),
+		FullName: &fullName,
+		Labels: append(supatms.SuiteLabels(suites),
+			&models.Label{Name: "package", Value: test.File},
+			&models.Label{Name: "framework", Value: "mocha"},
+			&models.Label{Name: "language", Value: "javascript"},
+		),
+	}
+	switch {
+	case pending:
+		ar.Status = "skipped"
+	case test.Err.Message != "" || test.Err.Stack != "":
+		ar.Status = "failed"
+		message := test.Err.Message
+		trace := test.Err.Stack
+		ar.StatusDetails = &models.StatusDetails{
+			Message: &message,
+			Trace:   &trace,
+		}
+	}
+	return ar
+}
@@ -0,0 +1,64 @@
+package mocha
+
+import (
+	"reflect"
+	"test-inspector/internal/parsertest"
+	"test-inspector/pkg/diag"
+	"testing"
+	"time"
+)
+
+func TestReadResults(t *testing.T) {
+	tests := []struct {
+		name string
+		want parsertest.Want
+	}{
+		{"rejects anonymous users", parsertest.Want{
+			FullName: "Auth rejects anonymous users", ParentSuite: "auth.spec", Suite: "Auth", Status: "passed"}},
+		{"works with email", parsertest.Want{
+			FullName: "Auth sign in works with email", ParentSuite: "auth.spec", Suite: "Auth", SubSuite: "sign in",
+			Status: "failed", Message: "expected 401 to equal 200"}},
+		{"skips phone", parsertest.Want{ParentSuite: "auth.spec", Suite: "Auth", SubSuite: "sign in", Status: "skipped"}},
+		{"redirects", parsertest.Want{ParentSuite: "auth.spec", Suite: "Auth", SubSuite: "sign in > with sso"}},
+		// describe blocks without own tests stay joined with their children
+		{"stores files", parsertest.Want{ParentSuite: "storage.spec", Suite: "Storage uploads"}},
+		{"responds", parsertest.Want{Suite: "health.spec", Status: "passed"}},
+	}
+	results, d := parsertest.Read(t, ReadResults, "testdata")
+	parsertest.Malformed(t, d, "broken.json", diag.Syntax)
+	if len(results) != len(tests) {
+		t.Fatalf("results %v, want %d", results.Names(), len(tests))
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsertest.Check(t, results.Find(t, tt.name), tt.want)
+		})
+	}
+
+	r := results.Find(t, "rejects anonymous users")
+	start := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC).UnixMilli()
+	if r.SubSuite != "" || r.Start != start || r.Stop != start+5 {
+		t.Errorf("sub suite %q, start %d, stop %d, want none, %d, %d", r.SubSuite, r.Start, r.Stop, start, start+5)
+	}
+}
+
+func TestDescribeTitles(t *testing.T) {
+	chains := map[string]bool{"Auth": true, "Auth sign in": true, "Auth sign in with sso": true, "Storage uploads": true}
+	tests := []struct {
+		chain string
+		want  []string
+	}{
+		{"", nil},
+		{"Auth", []string{"Auth"}},
+		{"Auth sign in", []string{"Auth", "sign in"}},
+		{"Auth sign in with sso", []string{"Auth", "sign in", "with sso"}},
+		{"Auth sign out", []string{"Auth", "sign out"}},
+		{"Authorization", []string{"Authorization"}},
+		{"Storage uploads", []string{"Storage uploads"}},
+	}
+	for _, tt := range tests {
+		if got := describeTitles(tt.chain, chains); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("describeTitles(%q) = %q, want %q", tt.chain, got, tt.want)
+		}
+	}
+}
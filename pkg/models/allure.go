@@ -69,7 +69,7 @@ func (r *AllureResult) FindLabel(name string) (string, bool) {
 // FindLinkByType trying to find the link in the labels.
 func (r *AllureResult) FindLinkByType(t string) (string, bool) {
 	for _, l := range r.Links {
-		if *l.Type == t {
+		if l.Type != nil && *l.Type == t {
 			return l.URL, true
 		}
 	}
@@ -0,0 +1,100 @@
+package models
+
+// AllureReportTestCase is a test case of the generated allure report, `data/test-cases/<uid>.json`.
+// @property {string} UID - The ID of the test case in the report, test cases are named by it.
+// @property {string} Name - The name of the test case.
+// @property {string} FullName - The full name of the test case.
+// @property {string} HistoryID - The ID of the test shared by all its runs.
+// @property Time - The start, stop and duration of the test case in milliseconds.
+// @property {string} Description - The description of the test case.
+// @property {string} DescriptionHTML - The description of the test case in HTML format.
+// @property {string} Status - The status of the test case: passed, failed, broken, skipped, unknown.
+// @property {string} StatusMessage - The message of the failure or skip.
+// @property {string} StatusTrace - The stack trace of the failure.
+// @property {bool} Flaky - If the test case is flaky, this will be true.
+// @property {bool} Hidden - Retries of the test are hidden test cases.
+// @property {bool} Retry - If the test case is a retry of another one, this will be true.
+// @property {int} RetriesCount - The number of retries of the test case.
+// @property {[]*AllureReportStep} BeforeStages - Fixtures executed before the test.
+// @property TestStage - The body of the test with its steps and attachments.
+// @property {[]*AllureReportStep} AfterStages - Fixtures executed after the test.
+// @property {[]*Label} Labels - Labels of the test case.
+// @property {[]*Parameter} Parameters - Parameters of the test case.
+// @property {[]*Link} Links - Links of the test case.
+type AllureReportTestCase struct {
+	UID             string              `json:"uid"`
+	Name            string              `json:"name"`
+	FullName        string              `json:"fullName"`
+	HistoryID       string              `json:"historyId"`
+	Time            AllureReportTime    `json:"time"`
+	Description     string              `json:"description"`
+	DescriptionHTML string              `json:"descriptionHtml"`
+	Status          string              `json:"status"`
+	StatusMessage   string              `json:"statusMessage"`
+	StatusTrace     string              `json:"statusTrace"`
+	Flaky           bool                `json:"flaky"`
+	Hidden          bool                `json:"hidden"`
+	Retry           bool                `json:"retry"`
+	RetriesCount    int                 `json:"retriesCount"`
+	BeforeStages    []*AllureReportStep `json:"beforeStages"`
+	TestStage       *AllureReportStep   `json:"testStage"`
+	AfterStages     []*AllureReportStep `json:"afterStages"`
+	Labels          []*Label            `json:"labels"`
+	Parameters      []*Parameter        `json:"parameters"`
+	Links           []*Link             `json:"links"`
+}
+
+// AllureReportTime is the timing of the test case or the step in the generated allure report.
+// @property {int64} Start - The start time in milliseconds since the epoch.
+// @property {int64} Stop - The stop time in milliseconds since the epoch.
+// @property {int64} Duration - The duration in milliseconds.
+type AllureReportTime struct {
+	Start    int64 `json:"start"`
+	Stop     int64 `json:"stop"`
+	Duration int64 `json:"duration"`
+}
+
+// AllureReportStep is a step, a fixture or the test stage in the generated allure report.
+// @property {string} Name - The name of the step.
+// @property Time - The timing of the step.
+// @property {string} Status - The status of the step.
+// @property {string} StatusMessage - The message of the failure of the step.
+// @property {string} StatusTrace - The stack trace of the failure of the step.
+// @property {[]*AllureReportStep} Steps - Nested steps.
+// @property {[]*AllureReportAttachment} Attachments - Attachments of the step.
+// @property {[]*Parameter} Parameters - Parameters of the step.
+type AllureReportStep struct {
+	Name          string                    `json:"name"`
+	Time          AllureReportTime          `json:"time"`
+	Status        string                    `json:"status"`
+	StatusMessage string                    `json:"statusMessage"`
+	StatusTrace   string                    `json:"statusTrace"`
+	Steps         []*AllureReportStep       `json:"steps"`
+	Attachments   []*AllureReportAttachment `json:"attachments"`
+	Parameters    []*Parameter              `json:"parameters"`
+}
+
+// AllureReportAttachment is an attachment in the generated allure report.
+// @property {string} UID - The ID of the attachment.
+// @property {string} Name - The name of the attachment.
+// @property {string} Source - The name of the file in `data/attachments`.
+// @property {string} Type - The content type of the attachment.
+// @property {int64} Size - The size of the file in bytes.
+type AllureReportAttachment struct {
+	UID    string `json:"uid"`
+	Name   string `json:"name"`
+	Source string `json:"source"`
+	Type   string `json:"type"`
+	Size   int64  `json:"size"`
+}
+
+// AllureReportTree is a node of the tree of suites in the generated allure report, `data/suites.json`.
+// Groups have children, test cases are leaves with the UID of the test case.
+// @property {string} UID - The ID of the group or the test case.
+// @property {string} Name - The name of the group or the test case.
+// @property {[]*AllureReportTree} Children - Nested groups and test cases.
+type AllureReportTree struct {
+	UID      string              `json:"uid"`
+	Name     string              `json:"name"`
+	Children []*AllureReportTree `json:"children,omitempty"`
+}
@@ -0,0 +1,95 @@
+package models
+
+import "encoding/json"
+
+// CtrfReport is the root of the common test report format (ctrf) json.
+// @property {CtrfResults} Results - The results of the run.
+type CtrfReport struct {
+	Results *CtrfResults `json:"results"`
+}
+
+// CtrfResults is the run with the tool, the summary and the tests.
+// @property {CtrfTool} Tool - The tool that produced the report.
+// @property {CtrfSummary} Summary - The summary of the run.
+// @property {[]*CtrfTest} Tests - The tests of the run.
+// @property Environment - The environment the tests were run in.
+// @property Extra - Any additional information about the run.
+type CtrfResults struct {
+	Tool        CtrfTool               `json:"tool"`
+	Summary     CtrfSummary            `json:"summary"`
+	Tests       []*CtrfTest            `json:"tests"`
+	Environment map[string]interface{} `json:"environment,omitempty"`
+	Extra       map[string]interface{} `json:"extra,omitempty"`
+}
+
+// CtrfTool is the tool that produced the report.
+// @property {string} Name - The name of the tool.
+// @property {string} Version - The version of the tool.
+type CtrfTool struct {
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+}
+
+// CtrfSummary is the summary of the run.
+// @property {int} Tests - The number of tests.
+// @property {int} Passed - The number of passed tests.
+// @property {int} Failed - The number of failed tests.
+// @property {int} Pending - The number of pending tests.
+// @property {int} Skipped - The number of skipped tests.
+// @property {int} Other - The number of tests with other statuses.
+// @property {int64} Start - The start time of the run in milliseconds since the epoch.
+// @property {int64} Stop - The end time of the run in milliseconds since the epoch.
+type CtrfSummary struct {
+	Tests   int   `json:"tests"`
+	Passed  int   `json:"passed"`
+	Failed  int   `json:"failed"`
+	Pending int   `json:"pending"`
+	Skipped int   `json:"skipped"`
+	Other   int   `json:"other"`
+	Start   int64 `json:"start"`
+	Stop    int64 `json:"stop"`
+}
+
+// CtrfTest is a single test.
+// @property {string} Name - The name of the test.
+// @property {string} Status - The status of the test: passed, failed, skipped, pending, other.
+// @property {int64} Duration - The duration of the test in milliseconds.
+// @property {int64} Start - The start time of the test in milliseconds since the epoch.
+// @property {int64} Stop - The end time of the test in milliseconds since the epoch.
+// @property Suite - The suite of the test, a string joined with ` > ` or an array of suites.
+// @property {string} Message - The failure message.
+// @property {string} Trace - The stack trace of the failure.
+// @property {string} RawStatus - The status reported by the tool.
+// @property {[]string} Tags - Tags of the test.
+// @property {string} FilePath - The path to the test file.
+// @property {int} Retries - The number of retries.
+// @property {bool} Flaky - Whether the test passed after retries.
+// @property {[]*CtrfStep} Steps - Steps of the test.
+// @property Extra - Any additional information about the test.
+type CtrfTest struct {
+	Name      string                 `json:"name"`
+	Status    string                 `json:"status"`
+	Duration  int64                  `json:"duration"`
+	Start     int64                  `json:"start,omitempty"`
+	Stop      int64                  `json:"stop,omitempty"`
+	Suite     json.RawMessage        `json:"suite,omitempty"`
+	Message   string                 `json:"message,omitempty"`
+	Trace     string                 `json:"trace,omitempty"`
+	RawStatus string                 `json:"rawStatus,omitempty"`
+	Tags      []string               `json:"tags,omitempty"`
+	FilePath  string                 `json:"filePath,omitempty"`
+	Retries   int                    `json:"retries,omitempty"`
+	Flaky     bool                   `json:"flaky,omitempty"`
+	Steps     []*CtrfStep            `json:"steps,omitempty"`
+	Extra     map[string]interface{} `json:"extra,omitempty"`
+}
+
+// CtrfStep is a single step of the test.
+// @property {string} Name - The name of the step.
+// @property {string} Status - The status of the step.
+// @property Extra - Any additional information about the step, nested steps are kept here.
+type CtrfStep struct {
+	Name   string                 `json:"name"`
+	Status string                 `json:"status"`
+	Extra  map[string]interface{} `json:"extra,omitempty"`
+}
@@ -0,0 +1,91 @@
+package models
+
+// CucumberFeature is a single feature file of the cucumber json report.
+// @property {string} URI - The path to the feature file.
+// @property {string} ID - The ID of the feature.
+// @property {string} Keyword - The keyword of the feature, `Feature` in english.
+// @property {string} Name - The name of the feature.
+// @property {string} Description - The description of the feature.
+// @property {int} Line - The line of the feature in the feature file.
+// @property {[]*CucumberTag} Tags - Tags of the feature.
+// @property {[]*CucumberElement} Elements - Backgrounds and scenarios of the feature.
+type CucumberFeature struct {
+	URI         string             `json:"uri"`
+	ID          string             `json:"id"`
+	Keyword     string             `json:"keyword"`
+	Name        string             `json:"name"`
+	Description string             `json:"description"`
+	Line        int                `json:"line"`
+	Tags        []*CucumberTag     `json:"tags,omitempty"`
+	Elements    []*CucumberElement `json:"elements"`
+}
+
+// CucumberElement is a background or a scenario of the feature.
+// Scenario outlines are already expanded by cucumber to a scenario per example.
+// @property {string} ID - The ID of the scenario, for outlines it ends with examples name and row.
+// @property {string} Keyword - The keyword of the scenario, for ex. `Scenario Outline`.
+// @property {string} Type - The type of the element: `background` or `scenario`.
+// @property {string} Name - The name of the scenario.
+// @property {string} Description - The description of the scenario.
+// @property {int} Line - The line of the scenario in the feature file.
+// @property {string} StartTimestamp - The time the scenario started, not all implementations provide it.
+// @property {[]*CucumberTag} Tags - Tags of the scenario.
+// @property {[]*CucumberHook} Before - Before hooks of the scenario.
+// @property {[]*CucumberStep} Steps - Given/When/Then steps of the scenario.
+// @property {[]*CucumberHook} After - After hooks of the scenario.
+type CucumberElement struct {
+	ID             string          `json:"id"`
+	Keyword        string          `json:"keyword"`
+	Type           string          `json:"type"`
+	Name           string          `json:"name"`
+	Description    string          `json:"description"`
+	Line           int             `json:"line"`
+	StartTimestamp string          `json:"start_timestamp,omitempty"`
+	Tags           []*CucumberTag  `json:"tags,omitempty"`
+	Before         []*CucumberHook `json:"before,omitempty"`
+	Steps          []*CucumberStep `json:"steps,omitempty"`
+	After          []*CucumberHook `json:"after,omitempty"`
+}
+
+// CucumberStep is a single Given/When/Then step of the scenario.
+// @property {string} Keyword - The keyword of the step, for ex. `Given `.
+// @property {string} Name - The text of the step.
+// @property {int} Line - The line of the step in the feature file.
+// @property {CucumberResult} Result - The result of the step.
+type CucumberStep struct {
+	Keyword string         `json:"keyword"`
+	Name    string         `json:"name"`
+	Line    int            `json:"line"`
+	Result  CucumberResult `json:"result"`
+}
+
+// CucumberHook is a before or after hook of the scenario.
+// @property {CucumberMatch} Match - The location of the hook definition.
+// @property {CucumberResult} Result - The result of the hook.
+type CucumberHook struct {
+	Match  CucumberMatch  `json:"match"`
+	Result CucumberResult `json:"result"`
+}
+
+// CucumberMatch is the location of the step or hook definition.
+// @property {string} Location - The location of the definition in the code.
+type CucumberMatch struct {
+	Location string `json:"location,omitempty"`
+}
+
+// CucumberResult is the result of the step or hook.
+// @property {string} Status - The status of the step. Possible values are: passed, failed, skipped,
+// pending, undefined, ambiguous.
+// @property {int64} Duration - The duration of the step in nanoseconds.
+// @property {string} ErrorMessage - The error message with the stack trace of the failure.
+type CucumberResult struct {
+	Status       string `json:"status"`
+	Duration     int64  `json:"duration,omitempty"`
+	ErrorMessage string `json:"error_message,omitempty"`
+}
+
+// CucumberTag is a tag of the feature or the scenario.
+// @property {string} Name - The name of the tag with leading `@`.
+type CucumberTag struct {
+	Name string `json:"name"`
+}
@@ -0,0 +1,44 @@
+package models
+
+// JestReport is the output of `jest --json`.
+// @property {int64} StartTime - The start time of the run in milliseconds since the epoch.
+// @property {int} NumTotalTests - The number of tests in the run.
+// @property {[]*JestTestFile} TestResults - Results grouped by test files.
+type JestReport struct {
+	StartTime     int64           `json:"startTime"`
+	NumTotalTests *int            `json:"numTotalTests"`
+	TestResults   []*JestTestFile `json:"testResults"`
+}
+
+// JestTestFile is a result of a single test file.
+// @property {string} Name - The path to the test file.
+// @property {string} Status - The status of the test file.
+// @property {int64} StartTime - The start time of the file in milliseconds since the epoch.
+// @property {int64} EndTime - The end time of the file in milliseconds since the epoch.
+// @property {string} Message - The failure message of the file.
+// @property {[]*JestAssertionResult} AssertionResults - Results of the tests in the file.
+type JestTestFile struct {
+	Name             string                 `json:"name"`
+	Status           string                 `json:"status"`
+	StartTime        int64                  `json:"startTime"`
+	EndTime          int64                  `json:"endTime"`
+	Message          string                 `json:"message"`
+	AssertionResults []*JestAssertionResult `json:"assertionResults"`
+}
+
+// JestAssertionResult is a result of a single test.
+// @property {[]string} AncestorTitles - Names of `describe` blocks the test is nested in.
+// @property {string} FullName - The full name of the test.
+// @property {string} Title - The name of the test.
+// @property {string} Status - The status of the test. Possible values are: passed, failed, pending,
+// skipped, todo, disabled.
+// @property Duration - The duration of the test in milliseconds.
+// @property {[]string} FailureMessages - Failure messages with stack traces.
+type JestAssertionResult struct {
+	AncestorTitles  []string `json:"ancestorTitles"`
+	FullName        string   `json:"fullName"`
+	Title           string   `json:"title"`
+	Status          string   `json:"status"`
+	Duration        *int64   `json:"duration,omitempty"`
+	FailureMessages []string `json:"failureMessages,omitempty"`
+}
@@ -0,0 +1,63 @@
+package models
+
+import "encoding/xml"
+
+// JunitTestSuites is the root of the junit xml report with multiple suites.
+// Only the parts not available in go-junit are read: timestamps, hosts and reruns.
+// @property {[]*JunitTestSuite} TestSuites - Suites of the report.
+type JunitTestSuites struct {
+	XMLName    xml.Name          `xml:"testsuites"`
+	TestSuites []*JunitTestSuite `xml:"testsuite"`
+}
+
+// JunitTestSuite is a single suite of the junit xml report.
+// @property {string} Name - The name of the suite.
+// @property {string} Timestamp - The time the suite started, ISO 8601 usually without the time zone.
+// @property {string} Hostname - The host the suite was run on.
+// @property {[]*JunitTestCase} TestCases - Tests of the suite.
+// @property {[]*JunitTestSuite} TestSuites - Nested suites.
+type JunitTestSuite struct {
+	Name       string            `xml:"name,attr"`
+	Timestamp  string            `xml:"timestamp,attr"`
+	Hostname   string            `xml:"hostname,attr"`
+	TestCases  []*JunitTestCase  `xml:"testcase"`
+	TestSuites []*JunitTestSuite `xml:"testsuite"`
+}
+
+// JunitProperty is a single property of the test.
+// @property {string} Name - The name of the property.
+// @property {string} Value - The value of the property.
+type JunitProperty struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+}
+
+// JunitTestCase is a single test of the suite with the maven surefire rerun extensions.
+// @property {string} Name - The name of the test.
+// @property {string} Timestamp - The time the test started, not all reporters provide it.
+// @property {[]*JunitProperty} Properties - Properties of the test, some reporters write steps there.
+// @property {[]*JunitRerun} FlakyFailures - Failed runs of the test which passed on rerun.
+// @property {[]*JunitRerun} FlakyErrors - Errored runs of the test which passed on rerun.
+// @property {[]*JunitRerun} RerunFailures - Failed reruns of the failed test.
+// @property {[]*JunitRerun} RerunErrors - Errored reruns of the failed test.
+type JunitTestCase struct {
+	Name          string           `xml:"name,attr"`
+	Timestamp     string           `xml:"timestamp,attr"`
+	Properties    []*JunitProperty `xml:"properties>property"`
+	FlakyFailures []*JunitRerun    `xml:"flakyFailure"`
+	FlakyErrors   []*JunitRerun    `xml:"flakyError"`
+	RerunFailures []*JunitRerun    `xml:"rerunFailure"`
+	RerunErrors   []*JunitRerun    `xml:"rerunError"`
+}
+
+// JunitRerun is a single failed run or rerun of the test.
+// @property {string} Message - The failure message.
+// @property {string} Type - The type of the exception.
+// @property {string} StackTrace - The stack trace of the failure.
+// @property {string} Body - The stack trace if the reporter writes it as the element text.
+type JunitRerun struct {
+	Message    string `xml:"message,attr"`
+	Type       string `xml:"type,attr"`
+	StackTrace string `xml:"stackTrace"`
+	Body       string `xml:",chardata"`
+}
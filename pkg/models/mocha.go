@@ -0,0 +1,47 @@
+package models
+
+// MochaReport is the output of the mocha json reporter.
+// @property Stats - Summary of the run.
+// @property {[]*MochaTest} Tests - All tests of the run.
+// @property {[]*MochaTest} Pending - Pending tests of the run.
+// @property {[]*MochaTest} Failures - Failed tests of the run.
+// @property {[]*MochaTest} Passes - Passed tests of the run.
+type MochaReport struct {
+	Stats    *MochaStats  `json:"stats"`
+	Tests    []*MochaTest `json:"tests"`
+	Pending  []*MochaTest `json:"pending"`
+	Failures []*MochaTest `json:"failures"`
+	Passes   []*MochaTest `json:"passes"`
+}
+
+// MochaStats is a summary of the mocha run.
+// @property {string} Start - The start time of the run.
+// @property {int} Tests - The number of tests in the run.
+type MochaStats struct {
+	Start string `json:"start"`
+	Tests int    `json:"tests"`
+}
+
+// MochaTest is a single test of the mocha run.
+// @property {string} Title - The name of the test.
+// @property {string} FullTitle - Names of `describe` blocks joined with the name of the test.
+// @property {string} File - The path to the test file.
+// @property {int64} Duration - The duration of the test in milliseconds.
+// @property {int} CurrentRetry - The number of the retry of the test.
+// @property {MochaError} Err - The error of the failed test, empty object for passed tests.
+type MochaTest struct {
+	Title        string     `json:"title"`
+	FullTitle    string     `json:"fullTitle"`
+	File         string     `json:"file"`
+	Duration     int64      `json:"duration"`
+	CurrentRetry int        `json:"currentRetry"`
+	Err          MochaError `json:"err"`
+}
+
+// MochaError is the error of the failed test.
+// @property {string} Message - The error message.
+// @property {string} Stack - The stack trace of the error.
+type MochaError struct {
+	Message string `json:"message,omitempty"`
+	Stack   string `json:"stack,omitempty"`
+}
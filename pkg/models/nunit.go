@@ -0,0 +1,75 @@
+package models
+
+import "encoding/xml"
+
+// NUnitTestRun is the root of the nunit3 xml report.
+// @property {[]*NUnitTestSuite} Suites - Test assemblies of the run.
+type NUnitTestRun struct {
+	XMLName xml.Name          `xml:"test-run"`
+	Suites  []*NUnitTestSuite `xml:"test-suite"`
+}
+
+// NUnitTestSuite is an assembly, a namespace, a fixture or a parameterized method.
+// @property {string} Type - The type of the suite, for ex. Assembly, TestSuite, TestFixture,
+// ParameterizedMethod.
+// @property {string} Name - The name of the suite.
+// @property {string} FullName - The full name of the suite.
+// @property {string} ClassName - The full name of the class for fixtures.
+// @property {[]*NUnitProperty} Properties - Properties of the suite.
+// @property {[]*NUnitTestSuite} Suites - Nested suites.
+// @property {[]*NUnitTestCase} Tests - Tests of the suite.
+type NUnitTestSuite struct {
+	Type       string            `xml:"type,attr"`
+	Name       string            `xml:"name,attr"`
+	FullName   string            `xml:"fullname,attr"`
+	ClassName  string            `xml:"classname,attr"`
+	Properties []*NUnitProperty  `xml:"properties>property"`
+	Suites     []*NUnitTestSuite `xml:"test-suite"`
+	Tests      []*NUnitTestCase  `xml:"test-case"`
+}
+
+// NUnitTestCase is a single test.
+// @property {string} Name - The name of the test, for parameterized tests it contains the arguments.
+// @property {string} FullName - The full name of the test.
+// @property {string} MethodName - The name of the test method.
+// @property {string} ClassName - The full name of the test class including the namespace.
+// @property {string} Result - The result of the test: Passed, Failed, Skipped, Inconclusive, Warning.
+// @property {string} Label - The details of the result, for ex. Error, Cancelled, Ignored.
+// @property {string} StartTime - The time the test started.
+// @property {string} EndTime - The time the test finished.
+// @property {string} Duration - The duration of the test in seconds.
+// @property {[]*NUnitProperty} Properties - Properties of the test.
+// @property Failure - The failure message and stack trace.
+// @property Reason - The reason the test was skipped.
+// @property {string} Output - The output of the test.
+type NUnitTestCase struct {
+	Name       string           `xml:"name,attr"`
+	FullName   string           `xml:"fullname,attr"`
+	MethodName string           `xml:"methodname,attr"`
+	ClassName  string           `xml:"classname,attr"`
+	Result     string           `xml:"result,attr"`
+	Label      string           `xml:"label,attr"`
+	StartTime  string           `xml:"start-time,attr"`
+	EndTime    string           `xml:"end-time,attr"`
+	Duration   string           `xml:"duration,attr"`
+	Properties []*NUnitProperty `xml:"properties>property"`
+	Failure    *NUnitMessage    `xml:"failure"`
+	Reason     *NUnitMessage    `xml:"reason"`
+	Output     string           `xml:"output"`
+}
+
+// NUnitMessage is a failure or a skip reason.
+// @property {string} Message - The message.
+// @property {string} StackTrace - The stack trace of the failure.
+type NUnitMessage struct {
+	Message    string `xml:"message"`
+	StackTrace string `xml:"stack-trace"`
+}
+
+// NUnitProperty is a name/value pair of test properties, for ex. Category.
+// @property {string} Name - The name of the property.
+// @property {string} Value - The value of the property.
+type NUnitProperty struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+}
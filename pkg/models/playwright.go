@@ -0,0 +1,85 @@
+package models
+
+// PlaywrightReport is the output of the playwright json reporter.
+// @property Config - The config of the run.
+// @property {[]*PlaywrightSuite} Suites - Test files of the run.
+type PlaywrightReport struct {
+	Config *struct{}          `json:"config"`
+	Suites []*PlaywrightSuite `json:"suites"`
+}
+
+// PlaywrightSuite is a test file or a `describe` block.
+// @property {string} Title - The name of the suite, for test files it is the path to the file.
+// @property {string} File - The path to the test file.
+// @property {[]*PlaywrightSpec} Specs - Tests declared in the suite.
+// @property {[]*PlaywrightSuite} Suites - Nested `describe` blocks.
+type PlaywrightSuite struct {
+	Title  string             `json:"title"`
+	File   string             `json:"file"`
+	Specs  []*PlaywrightSpec  `json:"specs,omitempty"`
+	Suites []*PlaywrightSuite `json:"suites,omitempty"`
+}
+
+// PlaywrightSpec is a single test declared with `test()`.
+// @property {string} Title - The name of the test.
+// @property {string} ID - The ID of the test.
+// @property {string} File - The path to the test file.
+// @property {int} Line - The line of the test in the file.
+// @property {[]string} Tags - Tags of the test.
+// @property {[]*PlaywrightTest} Tests - Runs of the test, one per project.
+type PlaywrightSpec struct {
+	Title string            `json:"title"`
+	ID    string            `json:"id"`
+	File  string            `json:"file"`
+	Line  int               `json:"line"`
+	Tags  []string          `json:"tags,omitempty"`
+	Tests []*PlaywrightTest `json:"tests"`
+}
+
+// PlaywrightTest is a run of the test in a single project.
+// @property {string} ProjectName - The name of the project, for ex. browser.
+// @property {string} ExpectedStatus - The expected status of the test.
+// @property {string} Status - The outcome of the test: expected, unexpected, flaky, skipped.
+// @property {[]*PlaywrightResult} Results - Results of all attempts including retries.
+type PlaywrightTest struct {
+	ProjectName    string              `json:"projectName"`
+	ExpectedStatus string              `json:"expectedStatus"`
+	Status         string              `json:"status"`
+	Results        []*PlaywrightResult `json:"results"`
+}
+
+// PlaywrightResult is a result of a single attempt of the test.
+// @property {string} Status - The status of the attempt: passed, failed, timedOut, skipped, interrupted.
+// @property {int64} Duration - The duration of the attempt in milliseconds.
+// @property {string} StartTime - The start time of the attempt.
+// @property {int} Retry - The number of the retry.
+// @property {[]*PlaywrightError} Errors - Errors of the attempt.
+// @property {[]*PlaywrightStep} Steps - Steps of the attempt declared with `test.step()`.
+type PlaywrightResult struct {
+	Status    string             `json:"status"`
+	Duration  int64              `json:"duration"`
+	StartTime string             `json:"startTime"`
+	Retry     int                `json:"retry"`
+	Errors    []*PlaywrightError `json:"errors,omitempty"`
+	Steps     []*PlaywrightStep  `json:"steps,omitempty"`
+}
+
+// PlaywrightStep is a single step of the test.
+// @property {string} Title - The name of the step.
+// @property {int64} Duration - The duration of the step in milliseconds.
+// @property Error - The error of the failed step.
+// @property {[]*PlaywrightStep} Steps - Nested steps.
+type PlaywrightStep struct {
+	Title    string            `json:"title"`
+	Duration int64             `json:"duration"`
+	Error    *PlaywrightError  `json:"error,omitempty"`
+	Steps    []*PlaywrightStep `json:"steps,omitempty"`
+}
+
+// PlaywrightError is an error of the test or step.
+// @property {string} Message - The error message.
+// @property {string} Stack - The stack trace of the error.
+type PlaywrightError struct {
+	Message string `json:"message,omitempty"`
+	Stack   string `json:"stack,omitempty"`
+}
@@ -61,6 +61,19 @@ type Launch struct {
 // @property {int64} LaunchID - The ID of the launch that this test belongs to.
 // @property {int32} Duration - The duration of the test in milliseconds
 // @property {string} Steps - This is a JSON string that contains the steps of the test.
+// @property {string} Befores - This is a JSON string that contains the set up fixtures of the test.
+// @property {string} Afters - This is a JSON string that contains the tear down fixtures of the test.
+// @property {int16} Attempts - The number of times the test was run including retries, the result
+// of the final attempt is kept.
+// @property {[]*Label} Labels - Labels of the test, for ex. the testId label.
+// @property {StatusDetails} StatusDetails - The failure message and trace with secrets redacted.
+// @property {[]*Parameter} Parameters - Parameters of the parameterized test.
+// @property {[]*Link} Links - Links to issues and test cases.
+// @property Stage - The stage of the test, for ex. finished or interrupted.
+// @property HistoryID - The ID of the test shared by its runs.
+// @property {int64} Start - The start time of the test in milliseconds since the epoch.
+// @property {int64} Stop - The time when the test finished.
+// @property {[]*SupaAttachment} Attachments - Attachments of the test and its steps.
 // @property {[]*StepContainer} Stps - This is a slice of StepContainer structs.
 type SupaResult struct {
 	ID          uuid.UUID `json:"id"`
@@ -75,11 +88,38 @@ type SupaResult struct {
 	LaunchID    int64     `json:"launch_id"`
 	Duration    int32     `json:"duration"`
 	Steps       string    `json:"steps"`
+	Befores     string    `json:"befores,omitempty"`
+	Afters      string    `json:"afters,omitempty"`
+	Attempts    int16     `json:"attempts,omitempty"`
 	Labels      []*Label  `json:"labels,omitempty"`
 
+	StatusDetails *StatusDetails `json:"status_details,omitempty"`
+	Parameters    []*Parameter   `json:"parameters,omitempty"`
+	Links         []*Link        `json:"links,omitempty"`
+	Stage         *string        `json:"stage,omitempty"`
+	HistoryID     *string        `json:"history_id,omitempty"`
+	Start         int64          `json:"start,omitempty"`
+	Stop          int64          `json:"stop,omitempty"`
+
+	Attachments []*SupaAttachment `json:"attachments,omitempty"`
+
 	Stps []*StepContainer `json:"-"`
 }
 
+// SupaAttachment is a reference of the result to the attachment file stored in the storage bucket.
+// @property {string} Name - The name of the attachment.
+// @property {string} Type - The content type of the attachment.
+// @property {string} Source - The path of the attachment file in the results, it is not stored.
+// @property {string} Path - The path of the object in the storage bucket, empty if it was not uploaded.
+// @property {int64} Size - The size of the attachment in bytes.
+type SupaAttachment struct {
+	Name   string `json:"name"`
+	Type   string `json:"type,omitempty"`
+	Source string `json:"-"`
+	Path   string `json:"path,omitempty"`
+	Size   int64  `json:"size,omitempty"`
+}
+
 // StepContainer is a struct that is used to unmarshal the steps field of a SupaResult
 // @property {[]*StepContainer} StepContainer - This is a slice of StepContainer objects.
 // @property {string} Name - The name of the step
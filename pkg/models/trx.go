@@ -0,0 +1,92 @@
+package models
+
+import "encoding/xml"
+
+// TrxTestRun is the root of the visual studio test results (trx) file.
+// @property {[]*TrxUnitTestResult} Results - Results of the tests.
+// @property {[]*TrxUnitTest} TestDefinitions - Definitions of the tests with class and method names.
+// @property {[]*TrxTestEntry} TestEntries - Links between executions and test definitions.
+// @property {[]*TrxTestList} TestLists - Lists the tests are grouped to.
+type TrxTestRun struct {
+	XMLName         xml.Name             `xml:"TestRun"`
+	Results         []*TrxUnitTestResult `xml:"Results>UnitTestResult"`
+	TestDefinitions []*TrxUnitTest       `xml:"TestDefinitions>UnitTest"`
+	TestEntries     []*TrxTestEntry      `xml:"TestEntries>TestEntry"`
+	TestLists       []*TrxTestList       `xml:"TestLists>TestList"`
+}
+
+// TrxUnitTestResult is a result of a single test execution.
+// @property {string} ExecutionID - The ID of the execution.
+// @property {string} TestID - The ID of the test definition.
+// @property {string} TestName - The name of the test, for data driven tests it contains the arguments.
+// @property {string} ComputerName - The name of the host the test was executed on.
+// @property {string} Duration - The duration of the test in `hh:mm:ss.fffffff` format.
+// @property {string} StartTime - The time the test started.
+// @property {string} EndTime - The time the test finished.
+// @property {string} Outcome - The outcome of the test, for ex. Passed, Failed, NotExecuted.
+// @property {string} TestListID - The ID of the test list.
+// @property Output - The output and the error info of the test.
+// @property {[]*TrxUnitTestResult} InnerResults - Results of the data driven test rows.
+type TrxUnitTestResult struct {
+	ExecutionID  string               `xml:"executionId,attr"`
+	TestID       string               `xml:"testId,attr"`
+	TestName     string               `xml:"testName,attr"`
+	ComputerName string               `xml:"computerName,attr"`
+	Duration     string               `xml:"duration,attr"`
+	StartTime    string               `xml:"startTime,attr"`
+	EndTime      string               `xml:"endTime,attr"`
+	Outcome      string               `xml:"outcome,attr"`
+	TestListID   string               `xml:"testListId,attr"`
+	Output       *TrxOutput           `xml:"Output"`
+	InnerResults []*TrxUnitTestResult `xml:"InnerResults>UnitTestResult"`
+}
+
+// TrxOutput is the output of the test.
+// @property {string} StdOut - The standard output of the test.
+// @property {string} StdErr - The error output of the test.
+// @property {string} Message - The error message.
+// @property {string} StackTrace - The stack trace of the failure.
+type TrxOutput struct {
+	StdOut     string `xml:"StdOut"`
+	StdErr     string `xml:"StdErr"`
+	Message    string `xml:"ErrorInfo>Message"`
+	StackTrace string `xml:"ErrorInfo>StackTrace"`
+}
+
+// TrxUnitTest is a definition of the test.
+// @property {string} ID - The ID of the test.
+// @property {string} Name - The name of the test.
+// @property {string} Storage - The path to the test assembly.
+// @property {TrxTestMethod} TestMethod - The class and the method of the test.
+type TrxUnitTest struct {
+	ID         string        `xml:"id,attr"`
+	Name       string        `xml:"name,attr"`
+	Storage    string        `xml:"storage,attr"`
+	TestMethod TrxTestMethod `xml:"TestMethod"`
+}
+
+// TrxTestMethod is the method of the test.
+// @property {string} ClassName - The full name of the test class including the namespace.
+// @property {string} Name - The name of the test method.
+type TrxTestMethod struct {
+	ClassName string `xml:"className,attr"`
+	Name      string `xml:"name,attr"`
+}
+
+// TrxTestEntry links the test execution with the test definition.
+// @property {string} TestID - The ID of the test definition.
+// @property {string} ExecutionID - The ID of the execution.
+// @property {string} TestListID - The ID of the test list.
+type TrxTestEntry struct {
+	TestID      string `xml:"testId,attr"`
+	ExecutionID string `xml:"executionId,attr"`
+	TestListID  string `xml:"testListId,attr"`
+}
+
+// TrxTestList is a named group of tests.
+// @property {string} ID - The ID of the test list.
+// @property {string} Name - The name of the test list.
+type TrxTestList struct {
+	ID   string `xml:"id,attr"`
+	Name string `xml:"name,attr"`
+}
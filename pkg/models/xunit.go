@@ -0,0 +1,70 @@
+package models
+
+import "encoding/xml"
+
+// XUnitAssemblies is the root of the xunit v2 xml report.
+// @property {[]*XUnitAssembly} Assemblies - Test assemblies of the run.
+type XUnitAssemblies struct {
+	XMLName    xml.Name         `xml:"assemblies"`
+	Assemblies []*XUnitAssembly `xml:"assembly"`
+}
+
+// XUnitAssembly is a single test assembly.
+// @property {string} Name - The path to the assembly.
+// @property {string} RunDate - The date the assembly was run.
+// @property {string} RunTime - The time the assembly was run.
+// @property {[]*XUnitCollection} Collections - Test collections of the assembly.
+type XUnitAssembly struct {
+	Name        string             `xml:"name,attr"`
+	RunDate     string             `xml:"run-date,attr"`
+	RunTime     string             `xml:"run-time,attr"`
+	Collections []*XUnitCollection `xml:"collection"`
+}
+
+// XUnitCollection is a collection of tests, usually a test class.
+// @property {string} Name - The name of the collection.
+// @property {[]*XUnitTest} Tests - Tests of the collection.
+type XUnitCollection struct {
+	Name  string       `xml:"name,attr"`
+	Tests []*XUnitTest `xml:"test"`
+}
+
+// XUnitTest is a single test.
+// @property {string} Name - The display name of the test, by default the full name with arguments.
+// @property {string} Type - The full name of the test class including the namespace.
+// @property {string} Method - The name of the test method.
+// @property {string} Time - The duration of the test in seconds.
+// @property {string} Result - The result of the test: Pass, Fail, Skip, NotRun.
+// @property {[]*XUnitTrait} Traits - Traits of the test.
+// @property Failure - The failure message and stack trace.
+// @property {string} Reason - The reason the test was skipped.
+// @property {string} Output - The output of the test.
+type XUnitTest struct {
+	Name    string        `xml:"name,attr"`
+	Type    string        `xml:"type,attr"`
+	Method  string        `xml:"method,attr"`
+	Time    string        `xml:"time,attr"`
+	Result  string        `xml:"result,attr"`
+	Traits  []*XUnitTrait `xml:"traits>trait"`
+	Failure *XUnitFailure `xml:"failure"`
+	Reason  string        `xml:"reason"`
+	Output  string        `xml:"output"`
+}
+
+// XUnitFailure is a failure of the test.
+// @property {string} ExceptionType - The type of the exception.
+// @property {string} Message - The error message.
+// @property {string} StackTrace - The stack trace of the failure.
+type XUnitFailure struct {
+	ExceptionType string `xml:"exception-type,attr"`
+	Message       string `xml:"message"`
+	StackTrace    string `xml:"stack-trace"`
+}
+
+// XUnitTrait is a name/value pair of test traits, for ex. Category.
+// @property {string} Name - The name of the trait.
+// @property {string} Value - The value of the trait.
+type XUnitTrait struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+}
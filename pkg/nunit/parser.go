@@ -0,0 +1,185 @@
+package nunit
+
+import (
+	"encoding/xml"
+	"strconv"
+	"strings"
+	"test-inspector/pkg/diag"
+	"test-inspector/pkg/files"
+	"test-inspector/pkg/models"
+	"test-inspector/pkg/supatms"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Parser is a report parser for nunit3 xml reports.
+type Parser struct{}
+
+// Name returns the report type of nunit reports.
+func (Parser) Name() string {
+	return "nunit"
+}
+
+// Detect checks if the path is a nunit3 xml file or a folder containing one.
+func (Parser) Detect(resultsPath string) bool {
+	return files.HasXMLRoot(resultsPath, "test-run")
+}
+
+// ReadResults reads nunit3 report, see ReadResults.
+func (Parser) ReadResults(resultsPath string) (map[uuid.UUID]models.SupaResult, error) {
+	return ReadResults(resultsPath)
+}
+
+// ReadResults reads nunit3 xml file or all such files in the folder and returns them as a map of SupaResults
+func ReadResults(resultsPath string) (map[uuid.UUID]models.SupaResult, error) {
+	reports, err := files.List(resultsPath, ".xml")
+	if err != nil {
+		return nil, err
+	}
+
+	results := map[uuid.UUID]models.SupaResult{}
+	diagnostics := &diag.Collector{}
+	for _, f := range reports {
+		if files.XMLRoot(f) != "test-run" {
+			continue
+		}
+		run, err := parseFile(f)
+		if err != nil {
+			diagnostics.Add(diag.Skip(f, err))
+			continue
+		}
+		for _, s := range run.Suites {
+			for _, ar := range convertSuite(s, suitePath{}) {
+				res := supatms.ToResult(0, *ar, "")
+				results[res.ID] = res
+			}
+		}
+	}
+	return results, diagnostics.Err()
+}
+
+func parseFile(path string) (*models.NUnitTestRun, error) {
+	xmlFile, err := files.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var run models.NUnitTestRun
+	if err = xml.Unmarshal(xmlFile, &run); err != nil {
+		return nil, err
+	}
+	return &run, nil
+}
+
+// suitePath is the position of the test in the test-suite nesting.
+type suitePath struct {
+	assembly   string
+	namespaces []string
+	fixture    string
+	properties []*models.NUnitProperty
+}
+
+// convertSuite walks nested test suites collecting namespaces and fixtures for their tests.
+func convertSuite(s *models.NUnitTestSuite, path suitePath) []*models.AllureResult {
+	path.properties = append(append([]*models.NUnitProperty{}, path.properties...), s.Properties...)
+	switch s.Type {
+	case "Assembly":
+		path.assembly = s.Name
+	case "TestSuite", "SetUpFixture":
+		path.namespaces = append(append([]string{}, path.namespaces...), s.Name)
+	case "TestFixture", "GenericFixture", "ParameterizedFixture":
+		path.fixture = s.Name
+	}
+
+	res := []*models.AllureResult{}
+	for _, t := range s.Tests {
+		res = append(res, convertTest(t, path))
+	}
+	for _, inner := range s.Suites {
+		res = append(res, convertSuite(inner, path)...)
+	}
+	return res
+}
+
+func convertTest(t *models.NUnitTestCase, path suitePath) *models.AllureResult {
+	className := t.ClassName
+	if className == "" {
+		className = strings.TrimPrefix(strings.Join(path.namespaces, ".")+"."+path.fixture, ".")
+	}
+	namespace, class := supatms.SplitClassName(className)
+	name, args := supatms.SplitArguments(t.Name)
+	if t.MethodName != "" {
+		name = t.MethodName
+	}
+	fullName := t.FullName
+	ar := &models.AllureResult{
+		Name:     name,
+		Status:   convertResult(t.Result, t.Label),
+		UUID:     uuid.New(),
+		FullName: &fullName,
+		Labels: []*models.Label{
+			{Name: "suite", Value: class},
+			{Name: "parentSuite", Value: namespace},
+			{Name: "testClass", Value: className},
+			{Name: "testMethod", Value: name},
+			{Name: "package", Value: path.assembly},
+			{Name: "framework", Value: "nunit"},
+			{Name: "language", Value: "c#"},
+		},
+	}
+	for _, p := range append(append([]*models.NUnitProperty{}, path.properties...), t.Properties...) {
+		if p.Name == "Category" {
+			ar.Labels = append(ar.Labels, &models.Label{Name: "tag", Value: p.Value})
+		}
+	}
+	if args != "" {
+		ar.Parameters = []*models.Parameter{{Name: "arguments", Value: args}}
+	}
+	ar.Start, ar.Stop = timings(t)
+
+	details := t.Failure
+	if details == nil {
+		details = t.Reason
+	}
+	if details != nil {
+		message := strings.TrimSpace(details.Message)
+		trace := strings.TrimSpace(details.StackTrace)
+		ar.StatusDetails = &models.StatusDetails{
+			Message: &message,
+			Trace:   &trace,
+		}
+	}
+	return ar
+}
+
+func convertResult(result, label string) string {
+	switch result {
+	case "Passed", "Warning":
+		return "passed"
+	case "Failed":
+		if label == "Error" || label == "Cancelled" || label == "Invalid" {
+			return "broken"
+		}
+		return "failed"
+	case "Skipped", "Inconclusive":
+		return "skipped"
+	default:
+		return "unknown"
+	}
+}
+
+// timings returns start and stop of the test in milliseconds since the epoch.
+func timings(t *models.NUnitTestCase) (int64, int64) {
+	var start int64
+	for _, layout := range []string{"2006-01-02 15:04:05Z07:00", "2006-01-02 15:04:05.999999999Z07:00", time.RFC3339Nano} {
+		if st, err := time.Parse(layout, t.StartTime); err == nil {
+			start = st.UnixMilli()
+			break
+		}
+	}
+	duration, err := strconv.ParseFloat(t.Duration, 64)
+	if err != nil {
+		return start, start
+	}
+	return start, start + int64(duration*1000)
+}
@@ -0,0 +1,85 @@
+package nunit
+
+import (
+	"reflect"
+	"test-inspector/internal/parsertest"
+	"test-inspector/pkg/diag"
+	"testing"
+	"time"
+)
+
+func TestReadResults(t *testing.T) {
+	start := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC).UnixMilli()
+	tests := []struct {
+		name, args  string
+		want        parsertest.Want
+		tags        []string
+		start, stop int64
+	}{
+		{
+			name: "SignIn",
+			want: parsertest.Want{
+				FullName: "Example.Auth.AuthTests.SignIn", ParentSuite: "Example.Auth", Suite: "AuthTests",
+				Status: "failed", Message: "Expected: 200",
+			},
+			// categories of the fixture are inherited by tests
+			tags:  []string{"auth", "smoke"},
+			start: start, stop: start + 1500,
+		},
+		{
+			// the class name is taken from namespaces and the fixture if the test has none
+			name: "SignOut",
+			want: parsertest.Want{
+				FullName: "Example.Auth.AuthTests.SignOut", ParentSuite: "Example.Auth", Suite: "AuthTests",
+				Status: "skipped", Message: "needs a server",
+			},
+			tags:  []string{"auth"},
+			start: start + 2250, stop: start + 2250,
+		},
+		{
+			name: "Upload", args: `"a.txt",1`,
+			want: parsertest.Want{
+				FullName: `Example.UploadTests.Upload("a.txt",1)`, ParentSuite: "Example", Suite: "UploadTests",
+				Status: "passed",
+			},
+			tags:  []string{},
+			start: start + 3000, stop: start + 3250,
+		},
+		{
+			name: "Upload", args: `"b.txt",2`,
+			want:  parsertest.Want{Suite: "UploadTests", Status: "broken", Message: "disk full"},
+			tags:  []string{},
+			start: start + 4000, stop: start + 4100,
+		},
+	}
+	results, d := parsertest.Read(t, ReadResults, "testdata")
+	parsertest.Malformed(t, d, "broken.xml", diag.Truncated)
+	if len(results) != len(tests) {
+		t.Fatalf("results %v, want %d", results.Names(), len(tests))
+	}
+	for _, tt := range tests {
+		t.Run(tt.name+tt.args, func(t *testing.T) {
+			for _, r := range results.All(tt.name) {
+				args := ""
+				if len(r.Parameters) > 0 {
+					args = r.Parameters[0].Value
+				}
+				if args != tt.args {
+					continue
+				}
+				parsertest.Check(t, r, tt.want)
+				if tags := parsertest.Label(r, "tag"); !reflect.DeepEqual(tags, tt.tags) {
+					t.Errorf("tags %v, want %v", tags, tt.tags)
+				}
+				if r.Start != tt.start || r.Stop != tt.stop {
+					t.Errorf("start %d, stop %d, want %d, %d", r.Start, r.Stop, tt.start, tt.stop)
+				}
+				if pkg := parsertest.Label(r, "package"); !reflect.DeepEqual(pkg, []string{"Example.Tests.dll"}) {
+					t.Errorf("package %v, want the assembly", pkg)
+				}
+				return
+			}
+			t.Errorf("no result with arguments %q", tt.args)
+		})
+	}
+}
@@ -0,0 +1,187 @@
+package playwright
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"test-inspector/pkg/diag"
+	"test-inspector/pkg/files"
+	"test-inspector/pkg/models"
+	"test-inspector/pkg/supatms"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Parser is a report parser for the playwright json reporter output.
+type Parser struct{}
+
+// Name returns the report type of playwright json reports.
+func (Parser) Name() string {
+	return "playwright"
+}
+
+// Detect checks if the path is a playwright json file or a folder containing one.
+func (Parser) Detect(resultsPath string) bool {
+	reports, err := files.List(resultsPath, ".json")
+	if err != nil {
+		return false
+	}
+	for _, f := range reports {
+		if _, err := parseFile(f); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// ReadResults reads playwright json report, see ReadResults.
+func (Parser) ReadResults(resultsPath string) (map[uuid.UUID]models.SupaResult, error) {
+	return ReadResults(resultsPath)
+}
+
+// ReadResults reads playwright json report file or all such files in the folder
+// and returns a SupaResult for every test in every project
+func ReadResults(resultsPath string) (map[uuid.UUID]models.SupaResult, error) {
+	reports, err := files.List(resultsPath, ".json")
+	if err != nil {
+		return nil, err
+	}
+
+	results := map[uuid.UUID]models.SupaResult{}
+	diagnostics := &diag.Collector{}
+	for _, f := range reports {
+		report, err := parseFile(f)
+		if err != nil {
+			// other json files are skipped silently, only broken ones are reported
+			if diag.Malformed(err) {
+				diagnostics.Add(diag.Skip(f, err))
+			}
+			continue
+		}
+		for _, suite := range report.Suites {
+			for _, ar := range convertSuite(suite, suite.File, []string{}) {
+				steps, err := supatms.StepsJSON(ar)
+				if err != nil {
+					diagnostics.Add(diag.Warn(f, diag.Unsupported,
+						fmt.Sprintf("steps of %s could not be read: %v", ar.Name, err)))
+				}
+				res := supatms.ToResult(0, *ar, steps)
+				results[res.ID] = res
+			}
+		}
+	}
+	return results, diagnostics.Err()
+}
+
+// parseFile reads the file and returns an error if it is not a playwright report.
+func parseFile(path string) (*models.PlaywrightReport, error) {
+	jsonFile, err := files.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var report models.PlaywrightReport
+	if err = json.Unmarshal(jsonFile, &report); err != nil {
+		return nil, err
+	}
+	if report.Config == nil || report.Suites == nil {
+		return nil, fmt.Errorf("%s is not a playwright report", path)
+	}
+	return &report, nil
+}
+
+// convertSuite converts all specs of the file suite and its nested `describe` suites.
+func convertSuite(suite *models.PlaywrightSuite, file string, describes []string) []*models.AllureResult {
+	res := []*models.AllureResult{}
+	for _, spec := range suite.Specs {
+		for _, test := range spec.Tests {
+			if ar := convertTest(file, describes, spec, test); ar != nil {
+				res = append(res, ar)
+			}
+		}
+	}
+	for _, s := range suite.Suites {
+		res = append(res, convertSuite(s, file, append(append([]string{}, describes...), s.Title))...)
+	}
+	return res
+}
+
+func convertTest(file string, describes []string, spec *models.PlaywrightSpec, test *models.PlaywrightTest) *models.AllureResult {
+	if len(test.Results) == 0 {
+		return nil
+	}
+	suites := describes
+	if len(suites) == 0 {
+		suites = []string{strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))}
+	}
+	// only the last attempt matters, previous ones are retries
+	last := test.Results[len(test.Results)-1]
+	fullName := strings.Join(append(append([]string{file}, describes...), spec.Title), " > ")
+	ar := &models.AllureResult{
+		Name:     spec.Title,
+		Status:   convertStatus(last.Status),
+		Steps:    convertSteps(last.Steps),
+		UUID:     uuid.New(),
+		FullName: &fullName,
+		Labels: append(supatms.SuiteLabels(suites),
+			&models.Label{Name: "package", Value: file},
+			&models.Label{Name: "framework", Value: "playwright"},
+			&models.Label{Name: "language", Value: "javascript"},
+		),
+		Parameters: []*models.Parameter{
+			{Name: "project", Value: test.ProjectName},
+		},
+	}
+	for _, tag := range spec.Tags {
+		ar.Labels = append(ar.Labels, &models.Label{Name: "tag", Value: strings.TrimPrefix(tag, "@")})
+	}
+	if start, err := time.Parse(time.RFC3339Nano, last.StartTime); err == nil {
+		ar.Start = start.UnixMilli()
+	}
+	ar.Stop = ar.Start + last.Duration
+	ar.StatusDetails = &models.StatusDetails{
+		Flaky: test.Status == "flaky",
+	}
+	if len(last.Errors) > 0 {
+		ar.StatusDetails.Message = &last.Errors[0].Message
+		ar.StatusDetails.Trace = &last.Errors[0].Stack
+	}
+	return ar
+}
+
+func convertSteps(steps []*models.PlaywrightStep) []*models.Step {
+	res := []*models.Step{}
+	for _, s := range steps {
+		status := "passed"
+		var details *models.StatusDetails
+		if s.Error != nil {
+			status = "failed"
+			details = &models.StatusDetails{
+				Message: &s.Error.Message,
+				Trace:   &s.Error.Stack,
+			}
+		}
+		res = append(res, &models.Step{
+			Name:          s.Title,
+			Status:        &status,
+			StatusDetails: details,
+			Steps:         convertSteps(s.Steps),
+			Stop:          s.Duration,
+		})
+	}
+	return res
+}
+
+func convertStatus(status string) string {
+	switch status {
+	case "passed", "failed", "skipped":
+		return status
+	case "timedOut":
+		return "failed"
+	case "interrupted":
+		return "broken"
+	default:
+		return "unknown"
+	}
+}
@@ -0,0 +1,78 @@
+package playwright
+
+import (
+	"reflect"
+	"test-inspector/internal/parsertest"
+	"test-inspector/pkg/diag"
+	"testing"
+	"time"
+)
+
+func TestReadResults(t *testing.T) {
+	start := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC).UnixMilli()
+	tests := []struct {
+		name, project string
+		want          parsertest.Want
+		flaky         bool
+		stop          int64
+	}{
+		{
+			name: "rejects anonymous users", project: "chromium",
+			want: parsertest.Want{FullName: "auth.spec.ts > Auth > rejects anonymous users", Suite: "Auth", Status: "broken"},
+			stop: start + 3,
+		},
+		{
+			// only the last attempt is kept, the test is flaky
+			name: "works with email", project: "chromium",
+			want: parsertest.Want{
+				FullName: "auth.spec.ts > Auth > sign in > works with email", ParentSuite: "Auth", Suite: "sign in",
+				Status: "passed",
+				Steps:  []string{"open the page: passed", "sign in: passed", "  fill the form: passed"},
+			},
+			flaky: true, stop: start + 1030,
+		},
+		{
+			name: "works with email", project: "firefox",
+			want: parsertest.Want{
+				ParentSuite: "Auth", Suite: "sign in", Status: "failed", Message: "Test timeout of 30000ms exceeded.",
+				Steps: []string{"open the page: passed", "sign in: failed"},
+			},
+			stop: start + 30000,
+		},
+		{
+			// tests outside of describe blocks are grouped by the file
+			name: "responds", project: "chromium",
+			want: parsertest.Want{FullName: "health.spec.ts > responds", Suite: "health.spec", Status: "skipped"},
+			stop: start,
+		},
+	}
+	results, d := parsertest.Read(t, ReadResults, "testdata")
+	parsertest.Malformed(t, d, "broken.json", diag.Syntax)
+	if len(results) != len(tests) {
+		t.Fatalf("results %v, want %d", results.Names(), len(tests))
+	}
+	for _, tt := range tests {
+		t.Run(tt.name+" "+tt.project, func(t *testing.T) {
+			for _, r := range results.All(tt.name) {
+				if r.Parameters[0].Value != tt.project {
+					continue
+				}
+				parsertest.Check(t, r, tt.want)
+				if r.StatusDetails.Flaky != tt.flaky {
+					t.Errorf("flaky %v, want %v", r.StatusDetails.Flaky, tt.flaky)
+				}
+				if r.Stop != tt.stop {
+					t.Errorf("stop %d, want %d", r.Stop, tt.stop)
+				}
+				return
+			}
+			t.Errorf("no result of the project %s", tt.project)
+		})
+	}
+
+	for _, r := range results.All("works with email") {
+		if tags := parsertest.Label(r, "tag"); !reflect.DeepEqual(tags, []string{"smoke"}) {
+			t.Errorf("tags %v, want smoke without @", tags)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+// Package progress prints the progress of long operations, for ex. reading huge results folders.
+package progress
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// interval is the minimal time between two prints of the progress.
+const interval = 200 * time.Millisecond
+
+// Progress prints the number of processed items out of the total on a single line.
+// All methods are safe for concurrent use and do nothing on the nil Progress.
+type Progress struct {
+	mu      sync.Mutex
+	w       io.Writer
+	label   string
+	total   int
+	done    int
+	printed time.Time
+	shown   int
+}
+
+// New returns the progress of the operation, it returns nil if the writer is nil,
+// so callers do not need to check if the progress is enabled.
+func New(w io.Writer, label string, total int) *Progress {
+	if w == nil {
+		return nil
+	}
+	return &Progress{w: w, label: label, total: total}
+}
+
+// Add adds the number of processed items and prints the progress if it was not printed recently.
+func (p *Progress) Add(n int) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.done += n
+	if time.Since(p.printed) >= interval || p.done == p.total {
+		p.print()
+	}
+}
+
+// Finish prints the final progress and ends the line.
+func (p *Progress) Finish() {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.printed.IsZero() || p.shown != p.done {
+		p.print()
+	}
+	fmt.Fprintln(p.w)
+}
+
+func (p *Progress) print() {
+	p.printed = time.Now()
+	p.shown = p.done
+	if p.total > 0 {
+		fmt.Fprintf(p.w, "\r%s: %d/%d (%d%%)", p.label, p.done, p.total, p.done*100/p.total)
+		return
+	}
+	fmt.Fprintf(p.w, "\r%s: %d", p.label, p.done)
+}
@@ -0,0 +1,178 @@
+package report
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"test-inspector/pkg/diag"
+	"test-inspector/pkg/files"
+	"test-inspector/pkg/models"
+
+	"github.com/google/uuid"
+)
+
+// ExpandPaths expands glob patterns of the results paths and returns sorted unique paths.
+// Paths without glob characters are kept as is, so the parser can report if they are missing.
+func ExpandPaths(patterns []string) ([]string, error) {
+	seen := map[string]bool{}
+	paths := []string{}
+	for _, pattern := range patterns {
+		matches := []string{pattern}
+		if strings.ContainsAny(pattern, "*?[") {
+			var err error
+			if matches, err = filepath.Glob(pattern); err != nil {
+				return nil, fmt.Errorf("bad results path pattern %s: %v", pattern, err)
+			}
+			if len(matches) == 0 {
+				return nil, fmt.Errorf("no results found by pattern %s", pattern)
+			}
+		}
+		for _, m := range matches {
+			m = filepath.Clean(m)
+			if !seen[m] {
+				seen[m] = true
+				paths = append(paths, m)
+			}
+		}
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
+// Check finds the parser and files of every results path without reading results,
+// so commands fail on wrong paths and unknown formats before they change anything.
+func Check(reportType string, resultsPaths []string) error {
+	paths, err := ExpandPaths(resultsPaths)
+	if err != nil {
+		return err
+	}
+	for _, p := range paths {
+		if _, err = Get(reportType, p); err != nil {
+			return fmt.Errorf("%s: %v", p, err)
+		}
+		found, err := files.List(p)
+		if err != nil {
+			return fmt.Errorf("%s: %v", p, err)
+		}
+		if len(found) == 0 {
+			return fmt.Errorf("%s: no files found", p)
+		}
+	}
+	return nil
+}
+
+// ReadAll reads results of all results paths and merges them into a single run.
+// The format is detected for every path separately in auto mode, so shards can be in different formats.
+// See merge for how duplicates between paths are resolved. Diagnostics of all paths are returned
+// together with merged results, see ReadResults.
+func ReadAll(reportType string, resultsPaths []string) (map[uuid.UUID]models.SupaResult, error) {
+	paths, err := ExpandPaths(resultsPaths)
+	if err != nil {
+		return nil, err
+	}
+	merged := map[uuid.UUID]models.SupaResult{}
+	index := map[string][]uuid.UUID{}
+	var diagnostics diag.Diagnostics
+	for _, p := range paths {
+		results, err := ReadResults(reportType, p)
+		d, err := diag.From(err)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", p, err)
+		}
+		diagnostics = append(diagnostics, d...)
+		merge(merged, index, results)
+	}
+	if len(diagnostics) > 0 {
+		return merged, diagnostics
+	}
+	return merged, nil
+}
+
+// Stream reads results of all results paths and hands them off to fn one by one.
+// A single path read by a Streamer is streamed without keeping results in memory,
+// results of multiple paths are merged first, see ReadAll. Diagnostics are returned after
+// all results are handed off.
+func Stream(reportType string, resultsPaths []string, fn func(models.SupaResult) error) error {
+	paths, err := ExpandPaths(resultsPaths)
+	if err != nil {
+		return err
+	}
+	if len(paths) == 1 {
+		p, err := Get(reportType, paths[0])
+		if err != nil {
+			return fmt.Errorf("%s: %v", paths[0], err)
+		}
+		if s, ok := p.(Streamer); ok {
+			return s.StreamResults(paths[0], func(r models.SupaResult) error {
+				return fn(prepare(r))
+			})
+		}
+	}
+	results, err := ReadAll(reportType, paths)
+	diagnostics, err := diag.From(err)
+	if err != nil {
+		return err
+	}
+	for _, r := range results {
+		if err = fn(r); err != nil {
+			return err
+		}
+	}
+	if len(diagnostics) > 0 {
+		return diagnostics
+	}
+	return nil
+}
+
+// merge adds results of the next path to the merged ones. Tests with the same names and suites
+// as in one of the previous paths are duplicates: the executed result wins over skipped one,
+// otherwise the result of the first path in sorted order is kept. Duplicates within the same path
+// are kept as a group, those are usually parameterized tests.
+func merge(merged map[uuid.UUID]models.SupaResult, index map[string][]uuid.UUID,
+	results map[uuid.UUID]models.SupaResult) {
+	groups := map[string][]uuid.UUID{}
+	for id, r := range results {
+		key := resultKey(r)
+		groups[key] = append(groups[key], id)
+	}
+	for key, ids := range groups {
+		prevIDs, ok := index[key]
+		if ok && bestRank(results, ids) <= bestRank(merged, prevIDs) {
+			continue
+		}
+		for _, id := range prevIDs {
+			delete(merged, id)
+		}
+		for _, id := range ids {
+			merged[id] = results[id]
+		}
+		index[key] = ids
+	}
+}
+
+func bestRank(results map[uuid.UUID]models.SupaResult, ids []uuid.UUID) int {
+	best := -1
+	for _, id := range ids {
+		if rank := statusRank(results[id].Status); rank > best {
+			best = rank
+		}
+	}
+	return best
+}
+
+func resultKey(r models.SupaResult) string {
+	return strings.Join([]string{r.Feature, r.ParentSuite, r.Suite, r.SubSuite, r.FullName, r.Name}, "\x00")
+}
+
+// statusRank ranks results by how much they tell about the test, executed ones are ranked the highest.
+func statusRank(status string) int {
+	switch status {
+	case "passed", "failed", "broken":
+		return 2
+	case "skipped", "pending":
+		return 0
+	default:
+		return 1
+	}
+}
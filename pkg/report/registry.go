@@ -0,0 +1,161 @@
+// Package report keeps the registry of all supported test report parsers.
+package report
+
+import (
+	"fmt"
+	"strings"
+	"test-inspector/pkg/allure"
+	"test-inspector/pkg/allurereport"
+	"test-inspector/pkg/ctrf"
+	"test-inspector/pkg/cucumber"
+	"test-inspector/pkg/diag"
+	"test-inspector/pkg/gotest"
+	"test-inspector/pkg/jest"
+	"test-inspector/pkg/junit"
+	"test-inspector/pkg/labelmap"
+	"test-inspector/pkg/mocha"
+	"test-inspector/pkg/models"
+	"test-inspector/pkg/nunit"
+	"test-inspector/pkg/playwright"
+	"test-inspector/pkg/redact"
+	"test-inspector/pkg/tap"
+	"test-inspector/pkg/trx"
+	"test-inspector/pkg/xunit"
+
+	"github.com/google/uuid"
+)
+
+// Auto is a report type to detect the format of the report by looking at the results path.
+const Auto = "auto"
+
+// Parser is an interface to read test results of a single report format.
+// @property Name - Returns the name of the format used as a report type.
+// @property Detect - Checks if the results path contains a report of this format.
+// @property ReadResults - Parses the report and returns a map of SupaResults.
+type Parser interface {
+	Name() string
+	Detect(resultsPath string) bool
+	ReadResults(resultsPath string) (map[uuid.UUID]models.SupaResult, error)
+}
+
+// Streamer is implemented by parsers that hand off results one by one instead of returning all of them,
+// so huge reports are not kept in memory.
+// @property StreamResults - Parses the report and calls fn for every SupaResult.
+type Streamer interface {
+	StreamResults(resultsPath string, fn func(models.SupaResult) error) error
+}
+
+// parsers are ordered, the first one that detects the format wins in auto mode
+var parsers = []Parser{
+	allurereport.Parser{},
+	allure.Parser{},
+	junit.Parser{},
+	gotest.Parser{},
+	cucumber.Parser{},
+	ctrf.Parser{},
+	jest.Parser{},
+	mocha.Parser{},
+	playwright.Parser{},
+	trx.Parser{},
+	nunit.Parser{},
+	xunit.Parser{},
+	tap.Parser{},
+}
+
+// mapping maps labels onto suite fields of results of every parser, nil keeps results as is.
+var mapping *labelmap.Mapping
+
+// redactor cleans failure details of results of every parser, nil keeps details as is.
+var redactor *redact.Redactor
+
+// Register adds a new parser to the registry or replaces the one with the same name.
+// It is not safe for concurrent use, so parsers should be registered on init.
+func Register(p Parser) {
+	for i, existing := range parsers {
+		if existing.Name() == p.Name() {
+			parsers[i] = p
+			return
+		}
+	}
+	parsers = append(parsers, p)
+}
+
+// SetMapping sets the mapping of labels applied to results of all parsers.
+// It is not safe for concurrent use, so the mapping should be set on init.
+func SetMapping(m *labelmap.Mapping) {
+	mapping = m
+}
+
+// SetRedactor sets the redactor of failure details applied to results of all parsers.
+// It is not safe for concurrent use, so the redactor should be set on init.
+func SetRedactor(r *redact.Redactor) {
+	redactor = r
+}
+
+// Names returns names of all registered parsers.
+func Names() []string {
+	names := make([]string, 0, len(parsers))
+	for _, p := range parsers {
+		names = append(names, p.Name())
+	}
+	return names
+}
+
+// Types returns all report types that can be passed to Get.
+func Types() []string {
+	return append([]string{Auto}, Names()...)
+}
+
+// Get returns the parser for the report type. For the auto type the format is detected by resultsPath.
+func Get(reportType, resultsPath string) (Parser, error) {
+	if reportType == Auto {
+		return Detect(resultsPath)
+	}
+	for _, p := range parsers {
+		if p.Name() == reportType {
+			return p, nil
+		}
+	}
+	return nil, fmt.Errorf("unsupported report type '%s', possible values: %s",
+		reportType, strings.Join(Types(), ", "))
+}
+
+// Detect returns the first parser that recognizes the report at resultsPath.
+func Detect(resultsPath string) (Parser, error) {
+	for _, p := range parsers {
+		if p.Detect(resultsPath) {
+			return p, nil
+		}
+	}
+	return nil, fmt.Errorf("could not detect report type for %s", resultsPath)
+}
+
+// ReadResults reads results at resultsPath with the parser for the report type
+// and maps their labels onto suite fields, see SetMapping, and redacts their failure details,
+// see SetRedactor. Results of well-formed files are returned together with diag.Diagnostics
+// of skipped malformed files.
+func ReadResults(reportType, resultsPath string) (map[uuid.UUID]models.SupaResult, error) {
+	p, err := Get(reportType, resultsPath)
+	if err != nil {
+		return nil, err
+	}
+	results, err := p.ReadResults(resultsPath)
+	diagnostics, err := diag.From(err)
+	if err != nil {
+		return nil, err
+	}
+	for id, r := range results {
+		results[id] = prepare(r)
+	}
+	if len(diagnostics) > 0 {
+		return results, diagnostics
+	}
+	return results, nil
+}
+
+// prepare maps labels of the result onto suite fields and redacts its failure details.
+func prepare(r models.SupaResult) models.SupaResult {
+	mapping.Apply(&r)
+	redactor.Apply(&r)
+	return r
+}
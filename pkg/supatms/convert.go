@@ -25,6 +25,14 @@ func ToResult(launchID int64, r models.AllureResult, steps string) models.SupaRe
 		LaunchID:    launchID,
 		Duration:    int32(r.Stop - r.Start),
 		Steps:       steps,
+
+		StatusDetails: r.StatusDetails,
+		Parameters:    r.Parameters,
+		Links:         r.Links,
+		Stage:         r.Stage,
+		HistoryID:     r.HistoryID,
+		Start:         r.Start,
+		Stop:          r.Stop,
 	}
 
 	return res
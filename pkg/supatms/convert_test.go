@@ -0,0 +1,41 @@
+package supatms
+
+import (
+	"reflect"
+	"test-inspector/pkg/models"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestToResultKeepsDetails(t *testing.T) {
+	message, trace, stage, history := "expected 200", "at auth.js:12", "finished", "h-sign-in"
+	ar := models.AllureResult{
+		UUID:          uuid.New(),
+		Name:          "signs in",
+		Status:        "failed",
+		StatusDetails: &models.StatusDetails{Message: &message, Trace: &trace},
+		Parameters:    []*models.Parameter{{Name: "user", Value: "ann"}},
+		Links:         []*models.Link{{Name: "AUTH-1", URL: "https://issues.example.com/AUTH-1"}},
+		Stage:         &stage,
+		HistoryID:     &history,
+		Start:         1714557600000,
+		Stop:          1714557601500,
+		Labels:        []*models.Label{{Name: "suite", Value: "Auth"}},
+	}
+	res := ToResult(7, ar, "")
+
+	if res.StatusDetails != ar.StatusDetails || !reflect.DeepEqual(res.Parameters, ar.Parameters) ||
+		!reflect.DeepEqual(res.Links, ar.Links) {
+		t.Errorf("details %v, parameters %v, links %v are not kept", res.StatusDetails, res.Parameters, res.Links)
+	}
+	if res.Stage != &stage || res.HistoryID != &history {
+		t.Errorf("stage %v, history id %v are not kept", res.Stage, res.HistoryID)
+	}
+	if res.Start != ar.Start || res.Stop != ar.Stop || res.Duration != 1500 {
+		t.Errorf("start %d, stop %d, duration %d, want %d, %d, 1500", res.Start, res.Stop, res.Duration, ar.Start, ar.Stop)
+	}
+	if res.LaunchID != 7 || res.Suite != "Auth" || res.FullName != "" {
+		t.Errorf("launch %d, suite %q, full name %q, want 7, Auth and no full name", res.LaunchID, res.Suite, res.FullName)
+	}
+}
@@ -0,0 +1,31 @@
+package supatms
+
+import (
+	"strings"
+	"test-inspector/pkg/models"
+)
+
+// SuiteLabels maps the hierarchy of suite names (from the root one) to suite labels.
+// The only suite becomes `suite`, with two of them the first one becomes `parentSuite`,
+// and all suites deeper than the second one are joined to `subSuite`.
+func SuiteLabels(names []string) []*models.Label {
+	switch len(names) {
+	case 0:
+		return []*models.Label{}
+	case 1:
+		return []*models.Label{
+			{Name: "suite", Value: names[0]},
+		}
+	case 2:
+		return []*models.Label{
+			{Name: "parentSuite", Value: names[0]},
+			{Name: "suite", Value: names[1]},
+		}
+	default:
+		return []*models.Label{
+			{Name: "parentSuite", Value: names[0]},
+			{Name: "suite", Value: names[1]},
+			{Name: "subSuite", Value: strings.Join(names[2:], " > ")},
+		}
+	}
+}
@@ -0,0 +1,23 @@
+package supatms
+
+import "strings"
+
+// SplitClassName splits the full class name to the namespace and the class name,
+// for ex. `Supabase.Tests.AuthTests` to `Supabase.Tests` and `AuthTests`.
+func SplitClassName(className string) (string, string) {
+	i := strings.LastIndex(className, ".")
+	if i < 0 {
+		return "", className
+	}
+	return className[:i], className[i+1:]
+}
+
+// SplitArguments splits the name of the parameterized test to the name and the arguments,
+// for ex. `SignIn("user", 1)` to `SignIn` and `"user", 1`.
+func SplitArguments(name string) (string, string) {
+	i := strings.Index(name, "(")
+	if i < 0 || !strings.HasSuffix(name, ")") {
+		return name, ""
+	}
+	return name[:i], name[i+1 : len(name)-1]
+}
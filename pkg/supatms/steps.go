@@ -0,0 +1,42 @@
+package supatms
+
+import (
+	"encoding/json"
+	"test-inspector/pkg/models"
+)
+
+// ToSteps converts allure steps of a test or step to the tree of StepContainers
+func ToSteps(r models.AllureStepContainer) []*models.StepContainer {
+	steps := []*models.StepContainer{}
+	var ctr int16
+	for _, s := range r.GetSteps() {
+		if s.Status == nil {
+			s.Status = stringRef("undefined")
+		}
+		stepInfo := models.StepContainer{
+			StepContainer: []*models.StepContainer{},
+			Name:          s.Name,
+			Status:        *s.Status,
+			Position:      ctr,
+		}
+
+		stepInfo.StepContainer = ToSteps(s)
+
+		steps = append(steps, &stepInfo)
+		ctr++
+	}
+	return steps
+}
+
+// StepsJSON converts allure steps of a test or step to the JSON string stored in SupaResult.
+// It returns an empty string if there are no steps.
+func StepsJSON(r models.AllureStepContainer) (string, error) {
+	if len(r.GetSteps()) == 0 {
+		return "", nil
+	}
+	stepsRaw, err := json.Marshal(ToSteps(r))
+	if err != nil {
+		return "", err
+	}
+	return string(stepsRaw), nil
+}
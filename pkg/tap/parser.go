@@ -0,0 +1,342 @@
+package tap
+
+import (
+	"bufio"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"test-inspector/pkg/diag"
+	"test-inspector/pkg/files"
+	"test-inspector/pkg/models"
+	"test-inspector/pkg/supatms"
+
+	"github.com/google/uuid"
+)
+
+var (
+	testPointRe = regexp.MustCompile(`^(not )?ok\b\s*(\d+)?\s*(?:-\s*)?(.*)$`)
+	directiveRe = regexp.MustCompile(`(?i)\s*#\s*(skip|todo)\S*\s*(.*)$`)
+	headerRe    = regexp.MustCompile(`^(TAP version \d+|1\.\.\d+|(not )?ok\b)`)
+)
+
+// Parser is a report parser for TAP (Test Anything Protocol) output.
+type Parser struct{}
+
+// Name returns the report type of tap reports.
+func (Parser) Name() string {
+	return "tap"
+}
+
+// Detect checks if the path is a tap file or a folder containing `.tap` files.
+func (Parser) Detect(resultsPath string) bool {
+	reports, err := files.List(resultsPath, ".tap")
+	if err != nil {
+		return false
+	}
+	for _, f := range reports {
+		if isTapFile(f) {
+			return true
+		}
+	}
+	return false
+}
+
+// ReadResults reads tap report, see ReadResults.
+func (Parser) ReadResults(resultsPath string) (map[uuid.UUID]models.SupaResult, error) {
+	return ReadResults(resultsPath)
+}
+
+// line is a single line of the tap output with its indentation.
+type line struct {
+	indent int
+	text   string
+}
+
+// testPoint is an `ok` or `not ok` line with its diagnostics and subtests.
+type testPoint struct {
+	ok          bool
+	description string
+	directive   string
+	reason      string
+	diagnostics map[string]string
+	subtests    []*testPoint
+}
+
+// ReadResults reads tap file or all `.tap` files in the folder
+// and returns top level test points as a map of SupaResults
+func ReadResults(resultsPath string) (map[uuid.UUID]models.SupaResult, error) {
+	reports, err := files.List(resultsPath, ".tap")
+	if err != nil {
+		return nil, err
+	}
+
+	results := map[uuid.UUID]models.SupaResult{}
+	diagnostics := &diag.Collector{}
+	for _, f := range reports {
+		lines, err := readLines(f)
+		if err != nil {
+			diagnostics.Add(diag.Skip(f, err))
+			continue
+		}
+		suite := strings.TrimSuffix(filepath.Base(f), filepath.Ext(f))
+		for i, tp := range parseLines(lines, 0) {
+			ar := convertTestPoint(suite, i, tp)
+			steps, err := supatms.StepsJSON(ar)
+			if err != nil {
+				diagnostics.Add(diag.Warn(f, diag.Unsupported,
+					fmt.Sprintf("steps of %s could not be read: %v", ar.Name, err)))
+			}
+			res := supatms.ToResult(0, *ar, steps)
+			results[res.ID] = res
+		}
+	}
+	return results, diagnostics.Err()
+}
+
+func readLines(path string) ([]line, error) {
+	f, err := files.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	lines := []line{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		raw := strings.ReplaceAll(strings.TrimRight(scanner.Text(), "\r"), "\t", "    ")
+		text := strings.TrimLeft(raw, " ")
+		lines = append(lines, line{indent: len(raw) - len(text), text: text})
+	}
+	return lines, scanner.Err()
+}
+
+func isTapFile(path string) bool {
+	lines, err := readLines(path)
+	if err != nil {
+		return false
+	}
+	for _, l := range lines {
+		if l.text != "" {
+			return headerRe.MatchString(l.text)
+		}
+	}
+	return false
+}
+
+// parseLines parses test points on the indentation level.
+// Subtests are indented and go before the test point they belong to,
+// diagnostics are indented yaml blocks right after the test point.
+func parseLines(lines []line, indent int) []*testPoint {
+	points := []*testPoint{}
+	var subtests []*testPoint
+	subtestName := ""
+	for i := 0; i < len(lines); i++ {
+		l := lines[i]
+		switch {
+		case l.text == "":
+			continue
+		case l.indent > indent:
+			j := i
+			for j < len(lines) && (lines[j].text == "" || lines[j].indent > indent) {
+				j++
+			}
+			subtests = parseLines(lines[i:j], l.indent)
+			if name := findSubtestName(lines[i:j], l.indent); name != "" {
+				subtestName = name
+			}
+			i = j - 1
+		case strings.HasPrefix(l.text, "# Subtest:"):
+			subtestName = strings.TrimSpace(strings.TrimPrefix(l.text, "# Subtest:"))
+		case strings.HasPrefix(l.text, "Bail out!"):
+			return points
+		case testPointRe.MatchString(l.text):
+			tp := parseTestPoint(l.text)
+			if tp.description == "" {
+				tp.description = subtestName
+			}
+			tp.subtests = subtests
+			subtests, subtestName = nil, ""
+			if i+1 < len(lines) && lines[i+1].text == "---" && lines[i+1].indent > indent {
+				j := i + 2
+				for j < len(lines) && !(lines[j].text == "..." && lines[j].indent == lines[i+1].indent) {
+					j++
+				}
+				tp.diagnostics = parseYAML(lines[i+2:j], lines[i+1].indent)
+				i = j
+			}
+			points = append(points, tp)
+		}
+	}
+	return points
+}
+
+func findSubtestName(lines []line, indent int) string {
+	for _, l := range lines {
+		if l.indent == indent && strings.HasPrefix(l.text, "# Subtest:") {
+			return strings.TrimSpace(strings.TrimPrefix(l.text, "# Subtest:"))
+		}
+	}
+	return ""
+}
+
+func parseTestPoint(text string) *testPoint {
+	m := testPointRe.FindStringSubmatch(text)
+	tp := &testPoint{
+		ok:          m[1] == "",
+		description: m[3],
+	}
+	if d := directiveRe.FindStringSubmatch(tp.description); d != nil {
+		tp.directive = strings.ToLower(d[1])
+		tp.reason = d[2]
+		tp.description = strings.TrimSpace(tp.description[:len(tp.description)-len(d[0])])
+	}
+	return tp
+}
+
+// parseYAML reads the top level keys of the yaml diagnostics block.
+// Nested mappings and block scalars are kept as raw text.
+func parseYAML(lines []line, indent int) map[string]string {
+	res := map[string]string{}
+	for i := 0; i < len(lines); i++ {
+		key, value, ok := strings.Cut(lines[i].text, ":")
+		if lines[i].indent != indent || !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		if value != "" && !strings.HasPrefix(value, "|") && !strings.HasPrefix(value, ">") {
+			res[key] = unquote(value)
+			continue
+		}
+		block := []string{}
+		j := i + 1
+		for ; j < len(lines) && (lines[j].text == "" || lines[j].indent > indent); j++ {
+			block = append(block, strings.Repeat(" ", maxInt(lines[j].indent-indent-2, 0))+lines[j].text)
+		}
+		separator := "\n"
+		if strings.HasPrefix(value, ">") {
+			separator = " "
+		}
+		res[key] = strings.TrimSpace(strings.Join(block, separator))
+		i = j - 1
+	}
+	return res
+}
+
+func unquote(value string) string {
+	if strings.HasPrefix(value, `"`) {
+		if s, err := strconv.Unquote(value); err == nil {
+			return s
+		}
+	}
+	if len(value) >= 2 && strings.HasPrefix(value, "'") && strings.HasSuffix(value, "'") {
+		return strings.ReplaceAll(value[1:len(value)-1], "''", "'")
+	}
+	return value
+}
+
+func convertTestPoint(suite string, i int, tp *testPoint) *models.AllureResult {
+	name := testName(i, tp)
+	fullName := suite + "." + name
+	start, stop := timings(tp)
+	return &models.AllureResult{
+		Name:          name,
+		Status:        status(tp),
+		StatusDetails: statusDetails(tp),
+		Steps:         convertSubtests(tp.subtests),
+		Start:         start,
+		Stop:          stop,
+		UUID:          uuid.New(),
+		FullName:      &fullName,
+		Labels: []*models.Label{
+			{Name: "suite", Value: suite},
+			{Name: "framework", Value: "tap"},
+		},
+	}
+}
+
+func convertSubtests(subtests []*testPoint) []*models.Step {
+	steps := []*models.Step{}
+	for i, tp := range subtests {
+		stepStatus := status(tp)
+		start, stop := timings(tp)
+		steps = append(steps, &models.Step{
+			Name:          testName(i, tp),
+			Status:        &stepStatus,
+			StatusDetails: statusDetails(tp),
+			Steps:         convertSubtests(tp.subtests),
+			Start:         start,
+			Stop:          stop,
+		})
+	}
+	return steps
+}
+
+func testName(i int, tp *testPoint) string {
+	if tp.description != "" {
+		return tp.description
+	}
+	return fmt.Sprintf("test %d", i+1)
+}
+
+func status(tp *testPoint) string {
+	switch {
+	case tp.directive == "skip":
+		return "skipped"
+	case tp.directive == "todo":
+		return "pending"
+	case tp.ok:
+		return "passed"
+	default:
+		return "failed"
+	}
+}
+
+// statusDetails returns the message and the trace from the diagnostics or the directive reason.
+func statusDetails(tp *testPoint) *models.StatusDetails {
+	message := tp.reason
+	if m, ok := tp.diagnostics["message"]; ok {
+		message = m
+	}
+	trace := []string{}
+	if stack, ok := tp.diagnostics["stack"]; ok {
+		trace = append(trace, stack)
+	}
+	keys := make([]string, 0, len(tp.diagnostics))
+	for k := range tp.diagnostics {
+		if k != "message" && k != "stack" {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		trace = append(trace, k+": "+tp.diagnostics[k])
+	}
+	if message == "" && len(trace) == 0 {
+		return nil
+	}
+	traceStr := strings.Join(trace, "\n")
+	return &models.StatusDetails{
+		Message: &message,
+		Trace:   &traceStr,
+	}
+}
+
+// timings returns the duration of the test from the diagnostics, tap does not have start times.
+func timings(tp *testPoint) (int64, int64) {
+	for _, key := range []string{"duration_ms", "duration"} {
+		if d, err := strconv.ParseFloat(tp.diagnostics[key], 64); err == nil {
+			return 0, int64(d)
+		}
+	}
+	return 0, 0
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
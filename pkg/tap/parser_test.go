@@ -0,0 +1,68 @@
+package tap
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"test-inspector/internal/parsertest"
+	"test-inspector/pkg/diag"
+	"testing"
+)
+
+func TestReadResults(t *testing.T) {
+	tests := []struct {
+		name string
+		want parsertest.Want
+	}{
+		{"sign in", parsertest.Want{
+			FullName: "auth.sign in", Suite: "auth", Status: "failed", Message: "subtests failed",
+			// subtests are steps
+			Steps: []string{"with sso: passed", "  redirects: passed", "wrong password: failed"},
+		}},
+		{"sign out", parsertest.Want{FullName: "auth.sign out", Status: "skipped", Message: "needs a server", Steps: []string{}}},
+		{"upload", parsertest.Want{Status: "pending", Message: "not implemented"}},
+		// test points without description are named by the number
+		{"test 4", parsertest.Want{FullName: "auth.test 4", Status: "passed"}},
+	}
+	results, d := parsertest.Read(t, ReadResults, "testdata")
+	if len(d) != 0 {
+		t.Errorf("diagnostics %v, want none", d)
+	}
+	if len(results) != len(tests) {
+		t.Fatalf("results %v, want %d top level test points", results.Names(), len(tests))
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsertest.Check(t, results.Find(t, tt.name), tt.want)
+		})
+	}
+
+	r := results.Find(t, "sign in")
+	if trace := *r.StatusDetails.Trace; trace != "at test.js:10\nat run.js:3\nduration_ms: 12.5" {
+		t.Errorf("trace %q, want the stack and other diagnostics", trace)
+	}
+	if r.Stop != 12 {
+		t.Errorf("stop %d, want the duration", r.Stop)
+	}
+}
+
+func TestReadResultsMalformed(t *testing.T) {
+	dir := t.TempDir()
+	huge := "TAP version 14\nok 1 - " + strings.Repeat("x", 1<<17) + "\n"
+	if err := os.WriteFile(filepath.Join(dir, "huge.tap"), []byte(huge), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(filepath.Join("testdata", "auth.tap"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = os.WriteFile(filepath.Join(dir, "auth.tap"), data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	results, d := parsertest.Read(t, ReadResults, dir)
+	parsertest.Malformed(t, d, "huge.tap", diag.Unsupported)
+	if len(results) != 4 {
+		t.Errorf("results %v, want tests of the well-formed file", results.Names())
+	}
+}
@@ -0,0 +1,229 @@
+package trx
+
+import (
+	"encoding/xml"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"test-inspector/pkg/diag"
+	"test-inspector/pkg/files"
+	"test-inspector/pkg/models"
+	"test-inspector/pkg/supatms"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// defaultList is the name of the test list visual studio adds all tests to by default.
+const defaultList = "Results Not in a List"
+
+// Parser is a report parser for visual studio test results (trx) files.
+type Parser struct{}
+
+// Name returns the report type of trx reports.
+func (Parser) Name() string {
+	return "trx"
+}
+
+// Detect checks if the path is a trx file or a folder containing one.
+func (Parser) Detect(resultsPath string) bool {
+	return files.HasXMLRoot(resultsPath, "TestRun")
+}
+
+// ReadResults reads trx report, see ReadResults.
+func (Parser) ReadResults(resultsPath string) (map[uuid.UUID]models.SupaResult, error) {
+	return ReadResults(resultsPath)
+}
+
+// ReadResults reads trx file or all trx files in the folder and returns them as a map of SupaResults
+func ReadResults(resultsPath string) (map[uuid.UUID]models.SupaResult, error) {
+	reports, err := files.List(resultsPath, ".trx", ".xml")
+	if err != nil {
+		return nil, err
+	}
+
+	results := map[uuid.UUID]models.SupaResult{}
+	diagnostics := &diag.Collector{}
+	for _, f := range reports {
+		if files.XMLRoot(f) != "TestRun" {
+			continue
+		}
+		run, err := parseFile(f)
+		if err != nil {
+			diagnostics.Add(diag.Skip(f, err))
+			continue
+		}
+		for _, ar := range convertRun(run) {
+			steps, err := supatms.StepsJSON(ar)
+			if err != nil {
+				diagnostics.Add(diag.Warn(f, diag.Unsupported,
+					fmt.Sprintf("steps of %s could not be read: %v", ar.Name, err)))
+			}
+			res := supatms.ToResult(0, *ar, steps)
+			results[res.ID] = res
+		}
+	}
+	return results, diagnostics.Err()
+}
+
+func parseFile(path string) (*models.TrxTestRun, error) {
+	xmlFile, err := files.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var run models.TrxTestRun
+	if err = xml.Unmarshal(xmlFile, &run); err != nil {
+		return nil, err
+	}
+	return &run, nil
+}
+
+// convertRun joins results with test definitions and lists through test entries.
+func convertRun(run *models.TrxTestRun) []*models.AllureResult {
+	definitions := map[string]*models.TrxUnitTest{}
+	for _, d := range run.TestDefinitions {
+		definitions[d.ID] = d
+	}
+	lists := map[string]string{}
+	for _, l := range run.TestLists {
+		lists[l.ID] = l.Name
+	}
+	executions := map[string]*models.TrxUnitTestResult{}
+	for _, r := range run.Results {
+		executions[r.ExecutionID] = r
+	}
+
+	entries := run.TestEntries
+	if len(entries) == 0 {
+		for _, r := range run.Results {
+			entries = append(entries, &models.TrxTestEntry{
+				TestID:      r.TestID,
+				ExecutionID: r.ExecutionID,
+				TestListID:  r.TestListID,
+			})
+		}
+	}
+
+	res := []*models.AllureResult{}
+	for _, e := range entries {
+		r, ok := executions[e.ExecutionID]
+		if !ok {
+			continue
+		}
+		def, ok := definitions[e.TestID]
+		if !ok {
+			def = &models.TrxUnitTest{Name: r.TestName}
+		}
+		res = append(res, convertResult(r, def, lists[e.TestListID]))
+	}
+	return res
+}
+
+func convertResult(r *models.TrxUnitTestResult, def *models.TrxUnitTest, list string) *models.AllureResult {
+	namespace, class := supatms.SplitClassName(def.TestMethod.ClassName)
+	name, args := supatms.SplitArguments(r.TestName)
+	if def.TestMethod.Name != "" {
+		name = def.TestMethod.Name
+	}
+	fullName := strings.TrimPrefix(def.TestMethod.ClassName+"."+r.TestName, ".")
+	ar := &models.AllureResult{
+		Name:     name,
+		Status:   convertOutcome(r.Outcome),
+		Steps:    convertInnerResults(r.InnerResults),
+		UUID:     uuid.New(),
+		FullName: &fullName,
+		Labels: []*models.Label{
+			{Name: "suite", Value: class},
+			{Name: "parentSuite", Value: namespace},
+			{Name: "testClass", Value: def.TestMethod.ClassName},
+			{Name: "testMethod", Value: name},
+			{Name: "host", Value: r.ComputerName},
+			{Name: "framework", Value: "trx"},
+			{Name: "language", Value: "c#"},
+		},
+	}
+	if def.Storage != "" {
+		ar.Labels = append(ar.Labels, &models.Label{Name: "package", Value: filepath.Base(def.Storage)})
+	}
+	// all tests are added to the default list if no lists are specified
+	if list != "" && list != defaultList {
+		ar.Labels = append(ar.Labels, &models.Label{Name: "tag", Value: list})
+	}
+	if args != "" {
+		ar.Parameters = []*models.Parameter{{Name: "arguments", Value: args}}
+	}
+	ar.Start, ar.Stop = timings(r)
+	if r.Output != nil {
+		message := r.Output.Message
+		trace := strings.TrimSpace(r.Output.StackTrace + "\n" + r.Output.StdErr)
+		ar.StatusDetails = &models.StatusDetails{
+			Message: &message,
+			Trace:   &trace,
+		}
+	}
+	return ar
+}
+
+// convertInnerResults converts rows of data driven tests to steps.
+func convertInnerResults(inner []*models.TrxUnitTestResult) []*models.Step {
+	steps := []*models.Step{}
+	for _, r := range inner {
+		status := convertOutcome(r.Outcome)
+		start, stop := timings(r)
+		steps = append(steps, &models.Step{
+			Name:   r.TestName,
+			Status: &status,
+			Steps:  convertInnerResults(r.InnerResults),
+			Start:  start,
+			Stop:   stop,
+		})
+	}
+	return steps
+}
+
+func convertOutcome(outcome string) string {
+	switch outcome {
+	case "Passed", "PassedButRunAborted":
+		return "passed"
+	case "Failed":
+		return "failed"
+	case "Error", "Timeout", "Aborted":
+		return "broken"
+	case "NotExecuted", "Inconclusive", "NotRunnable", "Pending", "Disconnected":
+		return "skipped"
+	default:
+		return "unknown"
+	}
+}
+
+// timings returns start and stop of the test in milliseconds since the epoch.
+func timings(r *models.TrxUnitTestResult) (int64, int64) {
+	var start int64
+	if t, err := time.Parse(time.RFC3339Nano, r.StartTime); err == nil {
+		start = t.UnixMilli()
+	}
+	return start, start + parseDuration(r.Duration).Milliseconds()
+}
+
+// parseDuration parses the duration in `hh:mm:ss.fffffff` format.
+func parseDuration(d string) time.Duration {
+	parts := strings.Split(d, ":")
+	if len(parts) != 3 {
+		return 0
+	}
+	hours, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return 0
+	}
+	minutes, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return 0
+	}
+	seconds, err := strconv.ParseFloat(parts[2], 64)
+	if err != nil {
+		return 0
+	}
+	return time.Duration(hours)*time.Hour + time.Duration(minutes)*time.Minute +
+		time.Duration(seconds*float64(time.Second))
+}
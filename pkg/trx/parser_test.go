@@ -0,0 +1,89 @@
+package trx
+
+import (
+	"reflect"
+	"test-inspector/internal/parsertest"
+	"test-inspector/pkg/diag"
+	"testing"
+	"time"
+)
+
+func TestReadResults(t *testing.T) {
+	start := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC).UnixMilli()
+	tests := []struct {
+		name        string
+		want        parsertest.Want
+		tags        []string
+		start, stop int64
+	}{
+		{
+			name: "SignIn",
+			want: parsertest.Want{
+				FullName: "Example.Auth.AuthTests.SignIn", ParentSuite: "Example.Auth", Suite: "AuthTests",
+				Status: "failed", Message: "Expected:<200>. Actual:<401>.", Steps: []string{},
+			},
+			tags:  []string{"Smoke"},
+			start: start, stop: start + 1500,
+		},
+		{
+			name: "SignOut",
+			want: parsertest.Want{FullName: "Example.Auth.AuthTests.SignOut", Suite: "AuthTests", Status: "skipped"},
+			// the default list is not a tag
+			tags:  []string{},
+			start: start + 2000, stop: start + 2000,
+		},
+		{
+			// rows of data driven tests are steps
+			name: "Upload",
+			want: parsertest.Want{
+				FullName: `Example.Storage.UploadTests.Upload("a.txt", 1)`, ParentSuite: "Example.Storage", Suite: "UploadTests",
+				Status: "passed", Steps: []string{"Upload (small): passed", "Upload (large): broken"},
+			},
+			tags:  []string{},
+			start: start + 3000, stop: start + 3250,
+		},
+	}
+	results, d := parsertest.Read(t, ReadResults, "testdata")
+	parsertest.Malformed(t, d, "broken.trx", diag.Truncated)
+	if len(results) != len(tests) {
+		t.Fatalf("results %v, want %d", results.Names(), len(tests))
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := results.Find(t, tt.name)
+			parsertest.Check(t, r, tt.want)
+			if tags := parsertest.Label(r, "tag"); !reflect.DeepEqual(tags, tt.tags) {
+				t.Errorf("tags %v, want %v", tags, tt.tags)
+			}
+			if r.Start != tt.start || r.Stop != tt.stop {
+				t.Errorf("start %d, stop %d, want %d, %d", r.Start, r.Stop, tt.start, tt.stop)
+			}
+			if pkg := parsertest.Label(r, "package"); !reflect.DeepEqual(pkg, []string{"Example.Tests.dll"}) {
+				t.Errorf("package %v, want the test assembly", pkg)
+			}
+		})
+	}
+
+	r := results.Find(t, "SignIn")
+	if *r.StatusDetails.Trace != "at Example.Auth.AuthTests.SignIn() in AuthTests.cs:line 12\nconnection reset" {
+		t.Errorf("trace %q, want the stack trace and the error output", *r.StatusDetails.Trace)
+	}
+	r = results.Find(t, "Upload")
+	if len(r.Parameters) != 1 || r.Parameters[0].Value != `"a.txt", 1` {
+		t.Errorf("parameters %v, want the arguments", r.Parameters)
+	}
+}
+
+func TestParseDuration(t *testing.T) {
+	tests := map[string]time.Duration{
+		"00:00:01.5000000": 1500 * time.Millisecond,
+		"01:02:03":         time.Hour + 2*time.Minute + 3*time.Second,
+		"":                 0,
+		"1.5":              0,
+	}
+	for d, want := range tests {
+		if got := parseDuration(d); got != want {
+			t.Errorf("parseDuration(%q) = %v, want %v", d, got, want)
+		}
+	}
+}
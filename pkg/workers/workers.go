@@ -0,0 +1,40 @@
+// Package workers runs jobs on a bounded pool of goroutines,
+// so huge results folders do not exhaust file descriptors and memory.
+package workers
+
+import (
+	"runtime"
+	"sync"
+)
+
+// Default returns the default number of workers, the number of CPUs.
+func Default() int {
+	return runtime.NumCPU()
+}
+
+// Run calls job for every index from 0 to count with at most n concurrent calls
+// and returns when all calls are finished. The default number of workers is used if n is not positive.
+func Run(n, count int, job func(i int)) {
+	if n <= 0 {
+		n = Default()
+	}
+	if n > count {
+		n = count
+	}
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < n; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				job(i)
+			}
+		}()
+	}
+	for i := 0; i < count; i++ {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+}
@@ -0,0 +1,154 @@
+package xunit
+
+import (
+	"encoding/xml"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"test-inspector/pkg/diag"
+	"test-inspector/pkg/files"
+	"test-inspector/pkg/models"
+	"test-inspector/pkg/supatms"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Parser is a report parser for xunit v2 xml reports.
+type Parser struct{}
+
+// Name returns the report type of xunit reports.
+func (Parser) Name() string {
+	return "xunit"
+}
+
+// Detect checks if the path is a xunit v2 xml file or a folder containing one.
+func (Parser) Detect(resultsPath string) bool {
+	return files.HasXMLRoot(resultsPath, "assemblies")
+}
+
+// ReadResults reads xunit v2 report, see ReadResults.
+func (Parser) ReadResults(resultsPath string) (map[uuid.UUID]models.SupaResult, error) {
+	return ReadResults(resultsPath)
+}
+
+// ReadResults reads xunit v2 xml file or all such files in the folder and returns them as a map of SupaResults
+func ReadResults(resultsPath string) (map[uuid.UUID]models.SupaResult, error) {
+	reports, err := files.List(resultsPath, ".xml")
+	if err != nil {
+		return nil, err
+	}
+
+	results := map[uuid.UUID]models.SupaResult{}
+	diagnostics := &diag.Collector{}
+	for _, f := range reports {
+		if files.XMLRoot(f) != "assemblies" {
+			continue
+		}
+		report, err := parseFile(f)
+		if err != nil {
+			diagnostics.Add(diag.Skip(f, err))
+			continue
+		}
+		for _, a := range report.Assemblies {
+			start := runStart(a)
+			for _, c := range a.Collections {
+				for _, t := range c.Tests {
+					res := supatms.ToResult(0, *convertTest(a, t, start), "")
+					results[res.ID] = res
+				}
+			}
+		}
+	}
+	return results, diagnostics.Err()
+}
+
+func parseFile(path string) (*models.XUnitAssemblies, error) {
+	xmlFile, err := files.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var report models.XUnitAssemblies
+	if err = xml.Unmarshal(xmlFile, &report); err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+func convertTest(a *models.XUnitAssembly, t *models.XUnitTest, start int64) *models.AllureResult {
+	namespace, class := supatms.SplitClassName(t.Type)
+	name, args := supatms.SplitArguments(t.Name)
+	if t.Method != "" {
+		name = t.Method
+	}
+	fullName := t.Name
+	ar := &models.AllureResult{
+		Name:     name,
+		Status:   convertResult(t.Result),
+		UUID:     uuid.New(),
+		FullName: &fullName,
+		Labels: []*models.Label{
+			{Name: "suite", Value: class},
+			{Name: "parentSuite", Value: namespace},
+			{Name: "testClass", Value: t.Type},
+			{Name: "testMethod", Value: name},
+			{Name: "package", Value: filepath.Base(a.Name)},
+			{Name: "framework", Value: "xunit"},
+			{Name: "language", Value: "c#"},
+		},
+	}
+	for _, trait := range t.Traits {
+		if trait.Name == "Category" {
+			ar.Labels = append(ar.Labels, &models.Label{Name: "tag", Value: trait.Value})
+			continue
+		}
+		ar.Labels = append(ar.Labels, &models.Label{Name: trait.Name, Value: trait.Value})
+	}
+	if args != "" {
+		ar.Parameters = []*models.Parameter{{Name: "arguments", Value: args}}
+	}
+	ar.Start = start
+	ar.Stop = start
+	if duration, err := strconv.ParseFloat(t.Time, 64); err == nil {
+		ar.Stop += int64(duration * 1000)
+	}
+
+	switch {
+	case t.Failure != nil:
+		message := strings.TrimSpace(t.Failure.Message)
+		trace := strings.TrimSpace(t.Failure.StackTrace)
+		ar.StatusDetails = &models.StatusDetails{
+			Message: &message,
+			Trace:   &trace,
+		}
+	case t.Reason != "":
+		message := strings.TrimSpace(t.Reason)
+		ar.StatusDetails = &models.StatusDetails{
+			Message: &message,
+		}
+	}
+	return ar
+}
+
+func convertResult(result string) string {
+	switch result {
+	case "Pass":
+		return "passed"
+	case "Fail":
+		return "failed"
+	case "Skip", "NotRun":
+		return "skipped"
+	default:
+		return "unknown"
+	}
+}
+
+// runStart returns the time the assembly was run in milliseconds since the epoch,
+// xunit does not keep start times of the tests.
+func runStart(a *models.XUnitAssembly) int64 {
+	t, err := time.Parse("2006-01-02 15:04:05", a.RunDate+" "+a.RunTime)
+	if err != nil {
+		return 0
+	}
+	return t.UnixMilli()
+}
@@ -0,0 +1,89 @@
+package xunit
+
+import (
+	"reflect"
+	"test-inspector/internal/parsertest"
+	"test-inspector/pkg/diag"
+	"testing"
+	"time"
+)
+
+func TestReadResults(t *testing.T) {
+	// xunit keeps only the start of the assembly run
+	start := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC).UnixMilli()
+	tests := []struct {
+		name, args string
+		want       parsertest.Want
+		tags       []string
+		stop       int64
+	}{
+		{
+			name: "SignIn",
+			want: parsertest.Want{
+				FullName: "Example.Auth.AuthTests.SignIn", ParentSuite: "Example.Auth", Suite: "AuthTests",
+				Status: "failed", Message: "Expected: 200",
+			},
+			tags: []string{"smoke"},
+			stop: start + 1500,
+		},
+		{
+			name: "SignOut",
+			want: parsertest.Want{
+				FullName: "Example.Auth.AuthTests.SignOut", ParentSuite: "Example.Auth", Suite: "AuthTests",
+				Status: "skipped", Message: "needs a server",
+			},
+			tags: []string{},
+			stop: start,
+		},
+		{
+			name: "Upload", args: `file: "a.txt", size: 1`,
+			want: parsertest.Want{
+				FullName: `Example.UploadTests.Upload(file: "a.txt", size: 1)`, ParentSuite: "Example", Suite: "UploadTests",
+				Status: "passed",
+			},
+			tags: []string{},
+			stop: start + 250,
+		},
+		{
+			name: "Upload", args: `file: "b.txt", size: 2`,
+			want: parsertest.Want{Suite: "UploadTests", Status: "passed"},
+			tags: []string{},
+			stop: start + 100,
+		},
+	}
+	results, d := parsertest.Read(t, ReadResults, "testdata")
+	parsertest.Malformed(t, d, "broken.xml", diag.Truncated)
+	if len(results) != len(tests) {
+		t.Fatalf("results %v, want %d", results.Names(), len(tests))
+	}
+	for _, tt := range tests {
+		t.Run(tt.name+tt.args, func(t *testing.T) {
+			for _, r := range results.All(tt.name) {
+				args := ""
+				if len(r.Parameters) > 0 {
+					args = r.Parameters[0].Value
+				}
+				if args != tt.args {
+					continue
+				}
+				parsertest.Check(t, r, tt.want)
+				if tags := parsertest.Label(r, "tag"); !reflect.DeepEqual(tags, tt.tags) {
+					t.Errorf("tags %v, want %v", tags, tt.tags)
+				}
+				if r.Start != start || r.Stop != tt.stop {
+					t.Errorf("start %d, stop %d, want %d, %d", r.Start, r.Stop, start, tt.stop)
+				}
+				if pkg := parsertest.Label(r, "package"); !reflect.DeepEqual(pkg, []string{"Example.Tests.dll"}) {
+					t.Errorf("package %v, want the assembly", pkg)
+				}
+				return
+			}
+			t.Errorf("no result with arguments %q", tt.args)
+		})
+	}
+
+	// traits other than categories are labels
+	if owner := parsertest.Label(results.Find(t, "SignIn"), "owner"); !reflect.DeepEqual(owner, []string{"auth-team"}) {
+		t.Errorf("owner %v, want the trait", owner)
+	}
+}